@@ -0,0 +1,167 @@
+package allnewsapitest
+
+import (
+	"sync"
+	"time"
+
+	allnewsapi "github.com/AllNewsAPI/go-sdk"
+)
+
+// FakeClock is an allnewsapi.Clock whose time only moves when Advance or Set
+// is called, making retries, backoff, hedging and pacing deterministic:
+//
+//	clock := allnewsapitest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+//	client, _ := allnewsapi.NewClient(key, allnewsapi.WithClock(clock))
+//	go client.Search(options)  // fails once, then waits to retry
+//	clock.BlockUntil(1)        // the retry is waiting
+//	clock.Advance(time.Second) // the retry is sent
+//
+// A FakeClock is safe for concurrent use.
+type FakeClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond // broadcast when a timer is added
+	now     time.Time
+	waiters []*fakeTimer
+}
+
+var _ allnewsapi.Clock = (*FakeClock)(nil)
+
+// NewFakeClock returns a FakeClock set to start.
+func NewFakeClock(start time.Time) *FakeClock {
+	c := &FakeClock{now: start}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now implements allnewsapi.Clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer implements allnewsapi.Clock. The timer fires once the clock is
+// advanced by at least d; a timer with d <= 0 fires immediately.
+func (c *FakeClock) NewTimer(d time.Duration) allnewsapi.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, ch: make(chan time.Time, 1)}
+	c.schedule(t, d)
+	return t
+}
+
+// After is the FakeClock counterpart of time.After, for code under test
+// that waits on the clock itself. The channel receives the time once the
+// clock is advanced by at least d, immediately when d <= 0.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// Sleep is the FakeClock counterpart of time.Sleep. It blocks until another
+// goroutine advances the clock by at least d, and returns immediately when
+// d <= 0. The sleeping goroutine counts as a waiter for BlockUntil.
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+// Advance moves the clock forward by d, firing the timers that are due.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setLocked(c.now.Add(d))
+}
+
+// Set moves the clock to t, firing the timers that are due. Moving the
+// clock backwards fires nothing.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setLocked(t)
+}
+
+// Waiters returns the number of timers that have not fired or been stopped.
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// BlockUntil blocks until at least n timers are waiting, which lets a test
+// advance the clock only once the code under test is sleeping.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+func (c *FakeClock) setLocked(t time.Time) {
+	c.now = t
+	waiting := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.deadline.After(t) {
+			waiting = append(waiting, w)
+			continue
+		}
+		w.fire(t)
+	}
+	c.waiters = waiting
+}
+
+// schedule arms t to fire after d. The caller holds c.mu.
+func (c *FakeClock) schedule(t *fakeTimer, d time.Duration) {
+	t.deadline = c.now.Add(d)
+	if d <= 0 {
+		t.fire(c.now)
+		return
+	}
+	c.waiters = append(c.waiters, t)
+	c.cond.Broadcast()
+}
+
+// unschedule disarms t and reports whether it was waiting. The caller holds
+// c.mu.
+func (c *FakeClock) unschedule(t *fakeTimer) bool {
+	for i, w := range c.waiters {
+		if w == t {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// fakeTimer is a Timer created by a FakeClock.
+type fakeTimer struct {
+	clock    *FakeClock
+	ch       chan time.Time
+	deadline time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.unschedule(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	active := t.clock.unschedule(t)
+	t.clock.schedule(t, d)
+	return active
+}
+
+// fire sends now on the channel, dropping it when the previous value was not
+// received, like time.Timer.
+func (t *fakeTimer) fire(now time.Time) {
+	select {
+	case t.ch <- now:
+	default:
+	}
+}
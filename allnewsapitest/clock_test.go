@@ -0,0 +1,90 @@
+package allnewsapitest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AllNewsAPI/go-sdk/allnewsapitest"
+)
+
+var clockStart = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// received reports whether ch has a value ready.
+func received(ch <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-ch:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFakeClockTimers(t *testing.T) {
+	clock := allnewsapitest.NewFakeClock(clockStart)
+	early := clock.NewTimer(time.Second)
+	late := clock.After(3 * time.Second)
+	stopped := clock.NewTimer(2 * time.Second)
+	if clock.Waiters() != 3 {
+		t.Fatalf("Waiters = %d, want 3", clock.Waiters())
+	}
+	if !stopped.Stop() || stopped.Stop() {
+		t.Error("Stop = false on a waiting timer or true on a stopped one")
+	}
+
+	clock.Advance(999 * time.Millisecond)
+	if _, ok := received(early.C()); ok {
+		t.Fatal("timer fired before its deadline")
+	}
+	clock.Advance(time.Millisecond)
+	if got, ok := received(early.C()); !ok || !got.Equal(clockStart.Add(time.Second)) {
+		t.Fatalf("timer sent %v (%v), want the time of its deadline", got, ok)
+	}
+
+	// Set jumps past the deadline; moving backwards fires nothing
+	clock.Set(clockStart)
+	if _, ok := received(late); ok {
+		t.Fatal("After fired when the clock moved backwards")
+	}
+	clock.Set(clockStart.Add(time.Hour))
+	if got, ok := received(late); !ok || !got.Equal(clockStart.Add(time.Hour)) {
+		t.Fatalf("After sent %v (%v), want the time the clock was set to", got, ok)
+	}
+	if _, ok := received(stopped.C()); ok || clock.Waiters() != 0 {
+		t.Errorf("stopped timer fired, or %d timers still wait", clock.Waiters())
+	}
+
+	// Reset rearms a fired timer relative to the current time
+	if early.Reset(time.Minute) {
+		t.Error("Reset = true on a fired timer")
+	}
+	clock.Advance(time.Minute)
+	if got, ok := received(early.C()); !ok || !got.Equal(clockStart.Add(time.Hour+time.Minute)) {
+		t.Errorf("reset timer sent %v (%v)", got, ok)
+	}
+
+	if _, ok := received(clock.After(0)); !ok {
+		t.Error("After(0) did not fire immediately")
+	}
+}
+
+func TestFakeClockSleep(t *testing.T) {
+	clock := allnewsapitest.NewFakeClock(clockStart)
+	done := make(chan struct{})
+	go func() {
+		clock.Sleep(5 * time.Second)
+		close(done)
+	}()
+
+	// The sleeper still waits on the clock until its deadline
+	clock.BlockUntil(1)
+	clock.Advance(4 * time.Second)
+	if clock.Waiters() != 1 {
+		t.Fatal("Sleep stopped waiting before the clock reached its deadline")
+	}
+	clock.Advance(time.Second)
+	<-done
+
+	// Non-positive durations do not block
+	clock.Sleep(0)
+	clock.Sleep(-time.Second)
+}
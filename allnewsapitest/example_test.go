@@ -0,0 +1,52 @@
+package allnewsapitest_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	allnewsapi "github.com/AllNewsAPI/go-sdk"
+	"github.com/AllNewsAPI/go-sdk/allnewsapitest"
+)
+
+// countNews is the code under test. It accepts an allnewsapi.Searcher, so
+// tests can pass a MockClient instead of a *allnewsapi.Client.
+func countNews(ctx context.Context, s allnewsapi.Searcher, query string) (int64, error) {
+	resp, err := s.SearchContext(ctx, &allnewsapi.SearchOptions{Query: query})
+	if err != nil {
+		return 0, err
+	}
+	return resp.TotalArticles, nil
+}
+
+func ExampleMockClient() {
+	mock := allnewsapitest.NewMockClient()
+	mock.QueueSearch(&allnewsapi.SearchResponse{TotalArticles: 42}, nil)
+
+	total, err := countNews(context.Background(), mock, "bitcoin")
+	fmt.Println(total, err)
+
+	for _, call := range mock.Calls() {
+		fmt.Println(call.Method, call.Options.Query)
+	}
+	// Output:
+	// 42 <nil>
+	// Search bitcoin
+}
+
+func ExampleFakeClock() {
+	clock := allnewsapitest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	done := make(chan struct{})
+	go func() {
+		clock.Sleep(time.Minute) // returns once the clock has advanced a minute
+		fmt.Println("woke at", clock.Now().Format(time.Kitchen))
+		close(done)
+	}()
+
+	clock.BlockUntil(1) // the goroutine is sleeping
+	clock.Advance(time.Minute)
+	<-done
+	// Output:
+	// woke at 12:01AM
+}
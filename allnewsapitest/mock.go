@@ -0,0 +1,195 @@
+// Package allnewsapitest provides test doubles for code using the
+// allnewsapi package.
+//
+// A typical table-driven test queues the responses each case expects and
+// asserts on the calls afterwards:
+//
+//	mock := allnewsapitest.NewMockClient()
+//	mock.QueueSearch(&allnewsapi.SearchResponse{TotalArticles: 1}, nil)
+//
+//	got, err := CountBitcoinNews(ctx, mock) // accepts an allnewsapi.Searcher
+//	...
+//	calls := mock.Calls()
+//	if len(calls) != 1 || calls[0].Options.Query != "bitcoin" {
+//		t.Errorf("unexpected calls: %+v", calls)
+//	}
+package allnewsapitest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	allnewsapi "github.com/AllNewsAPI/go-sdk"
+)
+
+// Method names recorded in Call.Method. Search and SearchContext share
+// MethodSearch, and Headlines and HeadlinesContext share MethodHeadlines.
+const (
+	MethodSearch          = "Search"
+	MethodHeadlines       = "Headlines"
+	MethodCount           = "Count"
+	MethodSearchSummaries = "SearchSummaries"
+)
+
+// Call is a call received by a MockClient.
+type Call struct {
+	Method  string                    // One of the Method constants
+	Options *allnewsapi.SearchOptions // Copy of the options passed in
+}
+
+// result is a canned response.
+type result struct {
+	resp      *allnewsapi.SearchResponse
+	summaries *allnewsapi.SummaryResponse
+	count     int64
+	err       error
+}
+
+// MockClient is an allnewsapi.Searcher returning queued responses. Each
+// method name has its own queue, consumed in order. A MockClient is safe for
+// concurrent use.
+type MockClient struct {
+	mu      sync.Mutex
+	queues  map[string][]result
+	calls   []Call
+	latency time.Duration
+	clock   allnewsapi.Clock
+}
+
+var _ allnewsapi.Searcher = (*MockClient)(nil)
+
+// NewMockClient returns a MockClient with empty queues.
+func NewMockClient() *MockClient {
+	return &MockClient{queues: make(map[string][]result)}
+}
+
+// QueueSearch queues the result of a future Search or SearchContext call.
+func (m *MockClient) QueueSearch(resp *allnewsapi.SearchResponse, err error) {
+	m.queue(MethodSearch, result{resp: resp, err: err})
+}
+
+// QueueHeadlines queues the result of a future Headlines or
+// HeadlinesContext call.
+func (m *MockClient) QueueHeadlines(resp *allnewsapi.SearchResponse, err error) {
+	m.queue(MethodHeadlines, result{resp: resp, err: err})
+}
+
+// QueueCount queues the result of a future Count call.
+func (m *MockClient) QueueCount(count int64, err error) {
+	m.queue(MethodCount, result{count: count, err: err})
+}
+
+// QueueSearchSummaries queues the result of a future SearchSummaries call.
+func (m *MockClient) QueueSearchSummaries(resp *allnewsapi.SummaryResponse, err error) {
+	m.queue(MethodSearchSummaries, result{summaries: resp, err: err})
+}
+
+func (m *MockClient) queue(method string, r result) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queues[method] = append(m.queues[method], r)
+}
+
+// SetLatency makes every call wait for d before returning, or until the
+// context is done.
+func (m *MockClient) SetLatency(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.latency = d
+}
+
+// SetClock makes the latency set by SetLatency elapse on clock, such as a
+// FakeClock, instead of the real clock.
+func (m *MockClient) SetClock(clock allnewsapi.Clock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clock = clock
+}
+
+// Calls returns the calls received so far, in order.
+func (m *MockClient) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// Search implements allnewsapi.Searcher.
+func (m *MockClient) Search(options *allnewsapi.SearchOptions, callOpts ...allnewsapi.CallOption) (*allnewsapi.SearchResponse, error) {
+	return m.SearchContext(context.Background(), options, callOpts...)
+}
+
+// SearchContext implements allnewsapi.Searcher.
+func (m *MockClient) SearchContext(ctx context.Context, options *allnewsapi.SearchOptions, _ ...allnewsapi.CallOption) (*allnewsapi.SearchResponse, error) {
+	r, err := m.call(ctx, MethodSearch, options)
+	return r.resp, err
+}
+
+// Headlines implements allnewsapi.Searcher.
+func (m *MockClient) Headlines(options *allnewsapi.SearchOptions, callOpts ...allnewsapi.CallOption) (*allnewsapi.SearchResponse, error) {
+	return m.HeadlinesContext(context.Background(), options, callOpts...)
+}
+
+// HeadlinesContext implements allnewsapi.Searcher.
+func (m *MockClient) HeadlinesContext(ctx context.Context, options *allnewsapi.SearchOptions, _ ...allnewsapi.CallOption) (*allnewsapi.SearchResponse, error) {
+	r, err := m.call(ctx, MethodHeadlines, options)
+	return r.resp, err
+}
+
+// Count implements allnewsapi.Searcher.
+func (m *MockClient) Count(ctx context.Context, options *allnewsapi.SearchOptions, _ ...allnewsapi.CallOption) (int64, error) {
+	r, err := m.call(ctx, MethodCount, options)
+	return r.count, err
+}
+
+// SearchSummaries implements allnewsapi.Searcher.
+func (m *MockClient) SearchSummaries(ctx context.Context, options *allnewsapi.SearchOptions, _ ...allnewsapi.CallOption) (*allnewsapi.SummaryResponse, error) {
+	r, err := m.call(ctx, MethodSearchSummaries, options)
+	return r.summaries, err
+}
+
+// call records the call and returns the next queued result for method.
+func (m *MockClient) call(ctx context.Context, method string, options *allnewsapi.SearchOptions) (result, error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, Call{Method: method, Options: options.Clone()})
+	latency, clock := m.latency, m.clock
+	var next *result
+	if queue := m.queues[method]; len(queue) > 0 {
+		next = &queue[0]
+		m.queues[method] = queue[1:]
+	}
+	m.mu.Unlock()
+
+	if latency > 0 {
+		if err := wait(ctx, clock, latency); err != nil {
+			return result{}, err
+		}
+	}
+
+	if next == nil {
+		return result{}, fmt.Errorf("allnewsapitest: no response queued for %s", method)
+	}
+	return *next, next.err
+}
+
+// wait waits for d on clock, or on the real clock when clock is nil.
+func wait(ctx context.Context, clock allnewsapi.Clock, d time.Duration) error {
+	var done <-chan time.Time
+	if clock != nil {
+		timer := clock.NewTimer(d)
+		defer timer.Stop()
+		done = timer.C()
+	} else {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		done = timer.C
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-done:
+		return nil
+	}
+}
@@ -0,0 +1,168 @@
+package allnewsapitest_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	allnewsapi "github.com/AllNewsAPI/go-sdk"
+	"github.com/AllNewsAPI/go-sdk/allnewsapitest"
+)
+
+func TestMockClientQueues(t *testing.T) {
+	mock := allnewsapitest.NewMockClient()
+	failure := errors.New("failure")
+	mock.QueueSearch(&allnewsapi.SearchResponse{TotalArticles: 1}, nil)
+	mock.QueueSearch(nil, failure)
+	mock.QueueHeadlines(&allnewsapi.SearchResponse{TotalArticles: 2}, nil)
+	ctx := context.Background()
+
+	// Each method consumes its own queue in order
+	if resp, err := mock.HeadlinesContext(ctx, nil); err != nil || resp.TotalArticles != 2 {
+		t.Errorf("HeadlinesContext = %+v, %v; want the queued headlines", resp, err)
+	}
+	if resp, err := mock.SearchContext(ctx, nil); err != nil || resp.TotalArticles != 1 {
+		t.Errorf("first SearchContext = %+v, %v; want the first queued response", resp, err)
+	}
+	if _, err := mock.SearchContext(ctx, nil); err != failure {
+		t.Errorf("second SearchContext error = %v, want the queued error", err)
+	}
+	if _, err := mock.SearchContext(ctx, nil); err == nil || !strings.Contains(err.Error(), "no response queued for Search") {
+		t.Errorf("SearchContext on an empty queue error = %v, want a no response queued error", err)
+	}
+}
+
+func TestMockClientMethods(t *testing.T) {
+	mock := allnewsapitest.NewMockClient()
+	failure := errors.New("failure")
+	mock.QueueSearch(&allnewsapi.SearchResponse{TotalArticles: 1}, nil)
+	mock.QueueHeadlines(&allnewsapi.SearchResponse{TotalArticles: 2}, nil)
+	mock.QueueCount(3, nil)
+	mock.QueueCount(0, failure)
+	mock.QueueSearchSummaries(&allnewsapi.SummaryResponse{TotalArticles: 4}, nil)
+	ctx := context.Background()
+
+	if resp, err := mock.Search(&allnewsapi.SearchOptions{Query: "a"}); err != nil || resp.TotalArticles != 1 {
+		t.Errorf("Search = %+v, %v; want the queued search", resp, err)
+	}
+	if resp, err := mock.Headlines(&allnewsapi.SearchOptions{Query: "b"}); err != nil || resp.TotalArticles != 2 {
+		t.Errorf("Headlines = %+v, %v; want the queued headlines", resp, err)
+	}
+	if n, err := mock.Count(ctx, &allnewsapi.SearchOptions{Query: "c"}); err != nil || n != 3 {
+		t.Errorf("Count = %d, %v; want the queued count", n, err)
+	}
+	if _, err := mock.Count(ctx, &allnewsapi.SearchOptions{Query: "e"}); err != failure {
+		t.Errorf("second Count error = %v, want the queued error", err)
+	}
+	if resp, err := mock.SearchSummaries(ctx, &allnewsapi.SearchOptions{Query: "d"}); err != nil || resp.TotalArticles != 4 {
+		t.Errorf("SearchSummaries = %+v, %v; want the queued summaries", resp, err)
+	}
+	if _, err := mock.SearchSummaries(ctx, &allnewsapi.SearchOptions{Query: "f"}); err == nil || !strings.Contains(err.Error(), "no response queued for SearchSummaries") {
+		t.Errorf("SearchSummaries on an empty queue error = %v, want a no response queued error", err)
+	}
+
+	var got []string
+	for _, call := range mock.Calls() {
+		got = append(got, call.Method+" "+call.Options.Query)
+	}
+	want := "Search a,Headlines b,Count c,Count e,SearchSummaries d,SearchSummaries f"
+	if strings.Join(got, ",") != want {
+		t.Errorf("calls = %q, want %q", strings.Join(got, ","), want)
+	}
+}
+
+func TestMockClientRecordsCopies(t *testing.T) {
+	mock := allnewsapitest.NewMockClient()
+	mock.QueueSearch(&allnewsapi.SearchResponse{}, nil)
+	options := &allnewsapi.SearchOptions{Query: "bitcoin", Lang: []string{"en"}}
+	mock.SearchContext(context.Background(), options)
+
+	// Changes made by the caller after the call are not seen in Calls
+	options.Query = "changed"
+	options.Lang[0] = "fr"
+
+	calls := mock.Calls()
+	if len(calls) != 1 || calls[0].Method != allnewsapitest.MethodSearch {
+		t.Fatalf("calls = %+v, want one Search call", calls)
+	}
+	if got := calls[0].Options; got.Query != "bitcoin" || got.Lang[0] != "en" {
+		t.Errorf("recorded options = %+v, want the options at the time of the call", got)
+	}
+}
+
+func TestMockClientLatency(t *testing.T) {
+	clock := allnewsapitest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	mock := allnewsapitest.NewMockClient()
+	mock.SetClock(clock)
+	mock.SetLatency(time.Second)
+	mock.QueueSearch(&allnewsapi.SearchResponse{}, nil)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := mock.SearchContext(context.Background(), nil)
+		done <- err
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(999 * time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("call returned before its latency elapsed")
+	default:
+	}
+	clock.Advance(time.Millisecond)
+	if err := <-done; err != nil {
+		t.Errorf("SearchContext: %v", err)
+	}
+
+	// The latency is interrupted by the context
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		_, err := mock.SearchContext(ctx, nil)
+		done <- err
+	}()
+	clock.BlockUntil(1)
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("SearchContext error = %v, want context.Canceled", err)
+	}
+}
+
+func TestMockClientConcurrent(t *testing.T) {
+	const n = 50
+	mock := allnewsapitest.NewMockClient()
+	for i := 0; i < n; i++ {
+		mock.QueueSearch(&allnewsapi.SearchResponse{TotalArticles: int64(i)}, nil)
+	}
+
+	var wg sync.WaitGroup
+	seen := make([]bool, n)
+	var mu sync.Mutex
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := mock.SearchContext(context.Background(), &allnewsapi.SearchOptions{})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			seen[resp.TotalArticles] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// Every queued response was returned exactly once
+	for i, ok := range seen {
+		if !ok {
+			t.Errorf("response %d was never returned", i)
+		}
+	}
+	if len(mock.Calls()) != n {
+		t.Errorf("recorded %d calls, want %d", len(mock.Calls()), n)
+	}
+}
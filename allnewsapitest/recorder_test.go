@@ -0,0 +1,108 @@
+package allnewsapitest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	allnewsapi "github.com/AllNewsAPI/go-sdk"
+	"github.com/AllNewsAPI/go-sdk/allnewsapitest"
+)
+
+func TestRecordThenReplay(t *testing.T) {
+	dir := t.TempDir()
+	server, shutdown := allnewsapitest.NewServer(
+		allnewsapitest.PagedFixture("search", 2, 3),
+		allnewsapitest.Fixture{Endpoint: "headlines", Status: http.StatusUnauthorized, Body: []byte(`{"message":"invalid key"}`)},
+	)
+
+	// Record against the fixture server with one key
+	recording, err := allnewsapi.NewClient("secret-key", allnewsapi.WithBaseURL(server.URL),
+		allnewsapi.WithTransport(allnewsapitest.NewRecorder(dir, allnewsapitest.ModeRecord)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := &allnewsapi.SearchOptions{Query: "bitcoin", Lang: []string{"en"}}
+	recorded, err := recording.SearchAll(context.Background(), options)
+	if err != nil {
+		t.Fatalf("recording SearchAll: %v", err)
+	}
+	_, recordedErr := recording.HeadlinesContext(context.Background(), nil)
+	shutdown()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 4 {
+		t.Fatalf("recorded %d cassettes, want 4", len(files))
+	}
+	for _, file := range files {
+		data, _ := os.ReadFile(file)
+		if strings.Contains(string(data), "secret-key") {
+			t.Errorf("%s contains the API key", filepath.Base(file))
+		}
+	}
+
+	// Replay offline with another key; the server is gone and every
+	// request must be served from the cassettes
+	replaying, err := allnewsapi.NewClient("other-key", allnewsapi.WithBaseURL(server.URL),
+		allnewsapi.WithTransport(allnewsapitest.NewRecorder(dir, allnewsapitest.ModeReplay)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := replaying.SearchAll(context.Background(), options)
+	if err != nil {
+		t.Fatalf("replaying SearchAll: %v", err)
+	}
+	if len(replayed) != len(recorded) || len(replayed) != 6 {
+		t.Fatalf("replayed %d articles, recorded %d, want 6", len(replayed), len(recorded))
+	}
+	for i := range replayed {
+		if !sameArticle(replayed[i], recorded[i]) {
+			t.Errorf("article %d: replayed %+v, recorded %+v", i, replayed[i], recorded[i])
+		}
+	}
+
+	var apiErr *allnewsapi.APIError
+	_, err = replaying.HeadlinesContext(context.Background(), nil)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || apiErr.Message != "invalid key" {
+		t.Errorf("replayed headlines error = %v, want the recorded %v", err, recordedErr)
+	}
+
+	// Requests that were not recorded fail without reaching the network
+	_, err = replaying.SearchContext(context.Background(), &allnewsapi.SearchOptions{Query: "ether"})
+	if err == nil || !strings.Contains(err.Error(), "no cassette") || !strings.Contains(err.Error(), "q=ether") {
+		t.Errorf("unrecorded search error = %v, want a no cassette error", err)
+	}
+}
+
+func TestReplayIgnoresParameterOrder(t *testing.T) {
+	dir := t.TempDir()
+	server, shutdown := allnewsapitest.NewServer(allnewsapitest.Fixture{Body: []byte{0xff, 0xfe, 0x00}})
+	defer shutdown()
+
+	record := allnewsapitest.NewRecorder(dir, allnewsapitest.ModeRecord)
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/v1/search?q=a&lang=en&apikey=k1", nil)
+	resp, err := record.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	replay := allnewsapitest.NewRecorder(dir, allnewsapitest.ModeReplay)
+	req, _ = http.NewRequest(http.MethodGet, "http://offline.invalid/v1/search?apikey=k2&lang=en&q=a", nil)
+	resp, err = replay.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Bodies that are not UTF-8 survive the cassette unchanged
+	body := make([]byte, 4)
+	n, _ := resp.Body.Read(body)
+	if string(body[:n]) != "\xff\xfe\x00" || resp.StatusCode != http.StatusOK {
+		t.Errorf("replayed %d %q, want 200 %q", resp.StatusCode, body[:n], "\xff\xfe\x00")
+	}
+}
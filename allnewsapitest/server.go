@@ -0,0 +1,192 @@
+package allnewsapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	allnewsapi "github.com/AllNewsAPI/go-sdk"
+)
+
+// Fixture is a canned API response served by NewServer.
+type Fixture struct {
+	// Endpoint is the endpoint name ("search", "headlines") or a path
+	// ("/v1/search"). An empty Endpoint matches every path.
+	Endpoint string
+
+	// Params lists query parameters that must have the given values for
+	// the fixture to match. Other parameters are ignored.
+	Params map[string]string
+
+	Status int         // Status code, 200 when zero
+	Header http.Header // Response headers
+	Body   []byte      // Response body
+
+	// BodyFunc, when set, is used instead of Body to build the response
+	// body for each request.
+	BodyFunc func(r *http.Request) []byte
+}
+
+// matches reports whether the fixture applies to r.
+func (f *Fixture) matches(r *http.Request) bool {
+	if f.Endpoint != "" {
+		path := f.Endpoint
+		if !strings.HasPrefix(path, "/") {
+			path = "/v1/" + path
+		}
+		if r.URL.Path != path {
+			return false
+		}
+	}
+
+	query := r.URL.Query()
+	for name, value := range f.Params {
+		if query.Get(name) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// NewServer starts a server answering requests with the first matching
+// fixture. Requests matching no fixture get a 404 whose body lists the
+// received path and parameters. Point a client at it with
+// allnewsapi.WithBaseURL(server.URL), and call the returned function to shut
+// it down.
+func NewServer(fixtures ...Fixture) (*httptest.Server, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := range fixtures {
+			f := &fixtures[i]
+			if !f.matches(r) {
+				continue
+			}
+
+			for name, values := range f.Header {
+				for _, value := range values {
+					w.Header().Add(name, value)
+				}
+			}
+			if w.Header().Get("Content-Type") == "" {
+				w.Header().Set("Content-Type", "application/json")
+			}
+
+			body := f.Body
+			if f.BodyFunc != nil {
+				body = f.BodyFunc(r)
+			}
+
+			status := f.Status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			w.WriteHeader(status)
+			w.Write(body)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(unmatchedMessage(r)))
+	}))
+	return server, server.Close
+}
+
+// unmatchedMessage describes a request no fixture matched.
+func unmatchedMessage(r *http.Request) string {
+	query := r.URL.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	params := make([]string, 0, len(names))
+	for _, name := range names {
+		value := query.Get(name)
+		if name == "apikey" {
+			value = "REDACTED"
+		}
+		params = append(params, name+"="+value)
+	}
+	return fmt.Sprintf("allnewsapitest: no fixture matches %s with params [%s]", r.URL.Path, strings.Join(params, " "))
+}
+
+// JSONFixture returns a fixture serving resp for the given endpoint.
+func JSONFixture(endpoint string, resp *allnewsapi.SearchResponse) Fixture {
+	return Fixture{Endpoint: endpoint, Body: ResponseBody(resp)}
+}
+
+// ResponseBody encodes resp as the API would. It panics if resp cannot be
+// encoded, which only happens with invalid values set with
+// Article.SetUnknown.
+func ResponseBody(resp *allnewsapi.SearchResponse) []byte {
+	body, err := json.Marshal(resp)
+	if err != nil {
+		panic("allnewsapitest: encoding response: " + err.Error())
+	}
+	return body
+}
+
+// baseTime is the publication time of the first generated article.
+var baseTime = time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+// GenerateArticles returns n articles numbered from first, with distinct
+// titles and URLs and publication times one minute apart, newest first.
+func GenerateArticles(first, n int) []allnewsapi.Article {
+	articles := make([]allnewsapi.Article, n)
+	for i := range articles {
+		id := first + i
+		a := &articles[i]
+		a.Title = fmt.Sprintf("Article %d", id)
+		a.Description = fmt.Sprintf("Description of article %d", id)
+		a.Category = "general"
+		a.Country = "us"
+		a.Region = "north-america"
+		a.Lang = "en"
+		a.Sentiment = "neutral"
+		a.URL = fmt.Sprintf("https://news.example.com/articles/%d", id)
+		a.Image = fmt.Sprintf("https://news.example.com/images/%d.jpg", id)
+		a.PublishedAt = baseTime.Add(-time.Duration(id) * time.Minute)
+		a.Source.Name = "Example News"
+		a.Source.URL = "https://news.example.com"
+	}
+	return articles
+}
+
+// PagedFixture returns a fixture serving pages of perPage generated
+// articles. Page N (from the page parameter, 1 when absent) reports
+// nextPage N+1 until the last page.
+func PagedFixture(endpoint string, perPage, pages int) Fixture {
+	return Fixture{
+		Endpoint: endpoint,
+		BodyFunc: func(r *http.Request) []byte {
+			page, err := strconv.Atoi(r.URL.Query().Get("page"))
+			if err != nil || page < 1 {
+				page = 1
+			}
+
+			resp := &allnewsapi.SearchResponse{
+				TotalArticles: int64(perPage * pages),
+				CurrentPage:   page,
+				TotalPages:    pages,
+			}
+			if page <= pages {
+				resp.Articles = GenerateArticles((page-1)*perPage+1, perPage)
+			}
+			if page < pages {
+				next := page + 1
+				resp.NextPage = &next
+			}
+			if page > 1 {
+				prev := page - 1
+				resp.PrevPage = &prev
+			}
+			return ResponseBody(resp)
+		},
+	}
+}
@@ -0,0 +1,145 @@
+package allnewsapitest_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	allnewsapi "github.com/AllNewsAPI/go-sdk"
+	"github.com/AllNewsAPI/go-sdk/allnewsapitest"
+)
+
+// newClient returns a client sending requests to baseURL.
+func newClient(t *testing.T, baseURL string, opts ...allnewsapi.ClientOption) *allnewsapi.Client {
+	t.Helper()
+	client, err := allnewsapi.NewClient("test-key", append([]allnewsapi.ClientOption{allnewsapi.WithBaseURL(baseURL)}, opts...)...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestServerMatching(t *testing.T) {
+	server, shutdown := allnewsapitest.NewServer(
+		allnewsapitest.Fixture{Endpoint: "search", Params: map[string]string{"q": "bitcoin"}, Body: []byte(`{"totalArticles":1}`)},
+		allnewsapitest.Fixture{Endpoint: "/v1/search", Body: []byte(`{"totalArticles":2}`)},
+		allnewsapitest.Fixture{Endpoint: "headlines", Status: http.StatusTooManyRequests,
+			Header: http.Header{"Retry-After": {"30"}}, Body: []byte(`{"message":"slow down"}`)},
+	)
+	defer shutdown()
+	client := newClient(t, server.URL)
+	ctx := context.Background()
+
+	// The first matching fixture wins; extra parameters are ignored
+	resp, err := client.SearchContext(ctx, &allnewsapi.SearchOptions{Query: "bitcoin", Lang: []string{"en"}})
+	if err != nil || resp.TotalArticles != 1 {
+		t.Errorf("search for bitcoin = %+v, %v; want the first fixture", resp, err)
+	}
+	resp, err = client.SearchContext(ctx, &allnewsapi.SearchOptions{Query: "ether"})
+	if err != nil || resp.TotalArticles != 2 {
+		t.Errorf("search for ether = %+v, %v; want the path fixture", resp, err)
+	}
+
+	_, err = client.HeadlinesContext(ctx, nil)
+	var apiErr *allnewsapi.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests || apiErr.Message != "slow down" {
+		t.Errorf("headlines error = %v, want the 429 of the fixture", err)
+	}
+}
+
+func TestServerHeaders(t *testing.T) {
+	server, shutdown := allnewsapitest.NewServer(
+		allnewsapitest.Fixture{Endpoint: "search", Header: http.Header{"X-Test": {"a", "b"}}},
+		allnewsapitest.Fixture{Endpoint: "headlines", Header: http.Header{"Content-Type": {"text/html"}}},
+	)
+	defer shutdown()
+
+	for _, tt := range []struct {
+		path, contentType string
+		test              []string
+	}{
+		{"/v1/search", "application/json", []string{"a", "b"}},
+		{"/v1/headlines", "text/html", nil},
+	} {
+		resp, err := http.Get(server.URL + tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if got := resp.Header.Get("Content-Type"); got != tt.contentType {
+			t.Errorf("%s: Content-Type = %q, want %q", tt.path, got, tt.contentType)
+		}
+		if got := resp.Header.Values("X-Test"); strings.Join(got, ",") != strings.Join(tt.test, ",") {
+			t.Errorf("%s: X-Test = %q, want %q", tt.path, got, tt.test)
+		}
+	}
+}
+
+func TestServerUnmatched(t *testing.T) {
+	server, shutdown := allnewsapitest.NewServer(allnewsapitest.Fixture{Endpoint: "headlines"})
+	defer shutdown()
+
+	resp, err := http.Get(server.URL + "/v1/search?q=bitcoin&apikey=secret&lang=en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	want := "allnewsapitest: no fixture matches /v1/search with params [apikey=REDACTED lang=en q=bitcoin]"
+	if resp.StatusCode != http.StatusNotFound || string(body) != want {
+		t.Errorf("got %d %q, want 404 %q", resp.StatusCode, body, want)
+	}
+}
+
+func TestServerFixtures(t *testing.T) {
+	articles := allnewsapitest.GenerateArticles(1, 2)
+	server, shutdown := allnewsapitest.NewServer(
+		allnewsapitest.JSONFixture("headlines", &allnewsapi.SearchResponse{TotalArticles: 2, Articles: articles}),
+		allnewsapitest.PagedFixture("search", 3, 4),
+	)
+	defer shutdown()
+	client := newClient(t, server.URL)
+	ctx := context.Background()
+
+	resp, err := client.HeadlinesContext(ctx, nil)
+	if err != nil {
+		t.Fatalf("HeadlinesContext: %v", err)
+	}
+	if len(resp.Articles) != 2 || !sameArticle(resp.Articles[1], articles[1]) {
+		t.Errorf("headlines = %+v, want the articles of the fixture", resp.Articles)
+	}
+
+	all, err := client.SearchAll(ctx, nil)
+	if err != nil {
+		t.Fatalf("SearchAll: %v", err)
+	}
+	if len(all) != 12 {
+		t.Fatalf("got %d articles, want 12", len(all))
+	}
+	for i, a := range all {
+		if want := allnewsapitest.GenerateArticles(i+1, 1)[0]; !sameArticle(a, want) {
+			t.Fatalf("article %d = %+v, want %+v", i, a, want)
+		}
+	}
+
+	// Past the last page, no articles and no next page
+	options := &allnewsapi.SearchOptions{}
+	options.SetPage(5)
+	resp, err = client.SearchContext(ctx, options)
+	if err != nil || len(resp.Articles) != 0 || resp.NextPage != nil || resp.PrevPage == nil || *resp.PrevPage != 4 {
+		t.Errorf("page 5 = %+v, %v; want an empty page after page 4", resp, err)
+	}
+}
+
+// sameArticle reports whether a and b have the same fields.
+func sameArticle(a, b allnewsapi.Article) bool {
+	return a.Title == b.Title && a.Description == b.Description && a.Content == b.Content &&
+		a.Category == b.Category && a.Country == b.Country && a.Region == b.Region &&
+		a.Lang == b.Lang && a.Sentiment == b.Sentiment && a.URL == b.URL && a.Image == b.Image &&
+		a.PublishedAt.Equal(b.PublishedAt) && a.Source == b.Source
+}
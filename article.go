@@ -0,0 +1,399 @@
+package allnewsapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Article represents a news article returned by the API.
+type Article struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Category    string    `json:"category"`
+	Content     string    `json:"content"`
+	Country     string    `json:"country"`
+	Region      string    `json:"region"`
+	Lang        string    `json:"lang"`
+	Sentiment   string    `json:"sentiment"`
+	URL         string    `json:"url"`
+	Image       string    `json:"image"`
+	PublishedAt time.Time `json:"publishedAt"`
+	Source      struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"source"`
+
+	// PublishedAtRaw holds the original publishedAt value when it could not
+	// be parsed, in which case PublishedAt is the zero time.
+	PublishedAtRaw string `json:"-"`
+
+	empty fieldSet      // fields present with an empty value when decoded
+	extra *articleExtra // nil unless decoding found state not held by the fields above
+}
+
+// articleExtra holds the decoded state of an Article that cannot be stored
+// in comparable fields: the original publishedAt token when it did not
+// parse, and unknown fields. It is shared by copies of an Article and never
+// modified once set. == compares the pointer, so an Article holding it is
+// only == to its copies.
+type articleExtra struct {
+	publishedAt json.RawMessage // publishedAt token that did not parse
+	rawFrom     string          // PublishedAtRaw decoded from publishedAt
+	unknown     map[string]json.RawMessage
+}
+
+// isZero reports whether e holds no state.
+func (e *articleExtra) isZero() bool {
+	return e.publishedAt == nil && len(e.unknown) == 0
+}
+
+// updateExtra replaces a.extra with a modified copy, or nil when the copy
+// holds no state.
+func (a *Article) updateExtra(update func(e *articleExtra)) {
+	var e articleExtra
+	if a.extra != nil {
+		e = *a.extra
+	}
+	update(&e)
+	if e.isZero() {
+		a.extra = nil
+		return
+	}
+	a.extra = &e
+}
+
+// Unknown returns the response fields not modeled by Article, keyed by their
+// JSON name, or nil when there are none. They are only captured when the
+// client uses WithUnknownFields, and are emitted again by MarshalJSON. The
+// returned map is a copy.
+func (a Article) Unknown() map[string]json.RawMessage {
+	if a.extra == nil || len(a.extra.unknown) == 0 {
+		return nil
+	}
+	unknown := make(map[string]json.RawMessage, len(a.extra.unknown))
+	for name, value := range a.extra.unknown {
+		unknown[name] = value
+	}
+	return unknown
+}
+
+// SetUnknown sets an unknown field emitted by MarshalJSON, or removes it
+// when value is nil. Names of fields modeled by Article are ignored.
+// Copies of a made before the call are not affected.
+func (a *Article) SetUnknown(name string, value json.RawMessage) {
+	if articleFields[strings.ToLower(name)] {
+		return
+	}
+	unknown := a.Unknown()
+	if value == nil {
+		delete(unknown, name)
+	} else {
+		if unknown == nil {
+			unknown = make(map[string]json.RawMessage)
+		}
+		unknown[name] = append(json.RawMessage(nil), value...)
+	}
+	a.setUnknown(unknown)
+}
+
+// setUnknown replaces the unknown fields of a.
+func (a *Article) setUnknown(unknown map[string]json.RawMessage) {
+	a.updateExtra(func(e *articleExtra) {
+		e.unknown = unknown
+		if len(unknown) == 0 {
+			e.unknown = nil
+		}
+	})
+}
+
+// fieldSet records a set of Article fields.
+type fieldSet uint16
+
+const (
+	fieldTitle fieldSet = 1 << iota
+	fieldDescription
+	fieldCategory
+	fieldContent
+	fieldCountry
+	fieldRegion
+	fieldLang
+	fieldSentiment
+	fieldURL
+	fieldImage
+	fieldSource
+	fieldSourceName
+	fieldSourceURL
+)
+
+// articleFields are the JSON names of the fields modeled by Article.
+var articleFields = map[string]bool{
+	"title":       true,
+	"description": true,
+	"category":    true,
+	"content":     true,
+	"country":     true,
+	"region":      true,
+	"lang":        true,
+	"sentiment":   true,
+	"url":         true,
+	"image":       true,
+	"publishedat": true,
+	"source":      true,
+}
+
+// timestampLayouts are the publishedAt formats accepted when decoding, in
+// the order they are tried. Layouts without a zone are interpreted as UTC.
+var timestampLayouts = []string{
+	time.RFC3339,
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// UnmarshalJSON decodes an Article. PublishedAt is parsed with each of the
+// supported layouts in turn and normalized to UTC; a value that matches
+// none of them leaves PublishedAt zero and is kept in PublishedAtRaw.
+func (a *Article) UnmarshalJSON(data []byte) error {
+	return a.decode(data, decodeOptions{})
+}
+
+// decode decodes data into a according to opts. Fields that are present
+// with an empty value, and a publishedAt that does not parse, are recorded
+// so that MarshalJSON writes them back unchanged; fields that are absent or
+// null are omitted again.
+func (a *Article) decode(data []byte, opts decodeOptions) error {
+	var aux struct {
+		Title       *string         `json:"title"`
+		Description *string         `json:"description"`
+		Category    *string         `json:"category"`
+		Content     *string         `json:"content"`
+		Country     *string         `json:"country"`
+		Region      *string         `json:"region"`
+		Lang        *string         `json:"lang"`
+		Sentiment   *string         `json:"sentiment"`
+		URL         *string         `json:"url"`
+		Image       *string         `json:"image"`
+		PublishedAt json.RawMessage `json:"publishedAt"`
+		Source      *struct {
+			Name *string `json:"name"`
+			URL  *string `json:"url"`
+		} `json:"source"`
+	}
+	if err := unmarshal(data, &aux, opts.strict); err != nil {
+		return err
+	}
+
+	*a = Article{}
+	var e articleExtra
+	setString := func(dst *string, value *string, field fieldSet) {
+		if value != nil {
+			*dst = *value
+			if *value == "" {
+				a.empty |= field
+			}
+		}
+	}
+	setString(&a.Title, aux.Title, fieldTitle)
+	setString(&a.Description, aux.Description, fieldDescription)
+	setString(&a.Category, aux.Category, fieldCategory)
+	setString(&a.Content, aux.Content, fieldContent)
+	setString(&a.Country, aux.Country, fieldCountry)
+	setString(&a.Region, aux.Region, fieldRegion)
+	setString(&a.Lang, aux.Lang, fieldLang)
+	if lang, err := NormalizeLang(a.Lang); err == nil {
+		a.Lang = lang
+	}
+	setString(&a.Sentiment, aux.Sentiment, fieldSentiment)
+	setString(&a.URL, aux.URL, fieldURL)
+	setString(&a.Image, aux.Image, fieldImage)
+	if aux.Source != nil {
+		setString(&a.Source.Name, aux.Source.Name, fieldSourceName)
+		setString(&a.Source.URL, aux.Source.URL, fieldSourceURL)
+		if a.Source.Name == "" && a.Source.URL == "" {
+			a.empty |= fieldSource
+		}
+	}
+
+	a.PublishedAt, a.PublishedAtRaw = parseTimestamp(aux.PublishedAt)
+	if raw := bytes.TrimSpace(aux.PublishedAt); a.PublishedAt.IsZero() && len(raw) > 0 && !bytes.Equal(raw, []byte("null")) {
+		e.publishedAt = append(json.RawMessage(nil), raw...)
+		e.rawFrom = a.PublishedAtRaw
+	}
+	if !e.isZero() {
+		extra := e
+		a.extra = &extra
+	}
+
+	if opts.captureUnknown {
+		return a.captureUnknown(data)
+	}
+	return nil
+}
+
+// emptyFields returns the fields of a that have an empty value.
+func (a *Article) emptyFields() fieldSet {
+	var set fieldSet
+	for _, f := range []struct {
+		bit   fieldSet
+		value string
+	}{
+		{fieldTitle, a.Title}, {fieldDescription, a.Description}, {fieldCategory, a.Category},
+		{fieldContent, a.Content}, {fieldCountry, a.Country}, {fieldRegion, a.Region},
+		{fieldLang, a.Lang}, {fieldSentiment, a.Sentiment}, {fieldURL, a.URL},
+		{fieldImage, a.Image}, {fieldSourceName, a.Source.Name}, {fieldSourceURL, a.Source.URL},
+	} {
+		if f.value == "" {
+			set |= f.bit
+		}
+	}
+	if a.Source.Name == "" && a.Source.URL == "" {
+		set |= fieldSource
+	}
+	return set
+}
+
+// presentEmpty reports whether field was present with an empty value when
+// a was decoded.
+func (a *Article) presentEmpty(field fieldSet) bool {
+	return a.empty&field != 0
+}
+
+// captureUnknown keeps the fields of data that Article does not model.
+// Field names are matched case-insensitively, like encoding/json.
+func (a *Article) captureUnknown(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for name := range fields {
+		if articleFields[strings.ToLower(name)] {
+			delete(fields, name)
+		}
+	}
+	a.setUnknown(fields)
+	return nil
+}
+
+// MarshalJSON encodes an Article with the field names and formats of the
+// API. A field is emitted when it has a non-zero value or was present with
+// an empty value in the decoded JSON, so decoding and re-encoding an article
+// preserves which fields it had; fields that were null are treated as
+// absent. PublishedAt is written in RFC 3339 format in UTC. When it is zero,
+// a decoded publishedAt that did not parse is written back as the original
+// JSON token, unless PublishedAtRaw was changed since, in which case
+// PublishedAtRaw is written as a string. Unknown fields are emitted as well.
+func (a Article) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	first := true
+	field := func(name string, value interface{}) error {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(data)
+		return nil
+	}
+
+	for _, f := range []struct {
+		name  string
+		bit   fieldSet
+		value string
+	}{
+		{"title", fieldTitle, a.Title},
+		{"description", fieldDescription, a.Description},
+		{"category", fieldCategory, a.Category},
+		{"content", fieldContent, a.Content},
+		{"country", fieldCountry, a.Country},
+		{"region", fieldRegion, a.Region},
+		{"lang", fieldLang, a.Lang},
+		{"sentiment", fieldSentiment, a.Sentiment},
+		{"url", fieldURL, a.URL},
+		{"image", fieldImage, a.Image},
+	} {
+		if f.value != "" || a.presentEmpty(f.bit) {
+			if err := field(f.name, f.value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	switch {
+	case !a.PublishedAt.IsZero():
+		if err := field("publishedAt", a.PublishedAt.UTC().Format(time.RFC3339Nano)); err != nil {
+			return nil, err
+		}
+	case a.extra != nil && a.extra.publishedAt != nil && a.extra.rawFrom == a.PublishedAtRaw:
+		if err := field("publishedAt", a.extra.publishedAt); err != nil {
+			return nil, err
+		}
+	case a.PublishedAtRaw != "":
+		if err := field("publishedAt", a.PublishedAtRaw); err != nil {
+			return nil, err
+		}
+	}
+
+	if a.Source.Name != "" || a.Source.URL != "" || a.presentEmpty(fieldSource|fieldSourceName|fieldSourceURL) {
+		source := make(map[string]string, 2)
+		if a.Source.Name != "" || a.presentEmpty(fieldSourceName) {
+			source["name"] = a.Source.Name
+		}
+		if a.Source.URL != "" || a.presentEmpty(fieldSourceURL) {
+			source["url"] = a.Source.URL
+		}
+		if err := field("source", source); err != nil {
+			return nil, err
+		}
+	}
+
+	unknown := a.Unknown()
+	names := make([]string, 0, len(unknown))
+	for name := range unknown {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := field(name, unknown[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// parseTimestamp parses a JSON encoded timestamp. When the value cannot be
+// parsed it returns the zero time and the raw value.
+func parseTimestamp(raw json.RawMessage) (time.Time, string) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return time.Time{}, ""
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return time.Time{}, string(raw)
+	}
+	if s = strings.TrimSpace(s); s == "" {
+		return time.Time{}, ""
+	}
+
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), ""
+		}
+	}
+	return time.Time{}, s
+}
@@ -0,0 +1,255 @@
+package allnewsapi
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPublishedAtFormats(t *testing.T) {
+	utc := func(year int, month time.Month, day, hour, min, sec, nsec int) time.Time {
+		return time.Date(year, month, day, hour, min, sec, nsec, time.UTC)
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantRaw string
+	}{
+		{"RFC 3339", `"2024-03-10T12:30:45Z"`, utc(2024, 3, 10, 12, 30, 45, 0), ""},
+		{"RFC 3339 with offset", `"2024-03-10T12:30:45+02:00"`, utc(2024, 3, 10, 10, 30, 45, 0), ""},
+		{"RFC 3339 with fraction", `"2024-03-10T12:30:45.123456789Z"`, utc(2024, 3, 10, 12, 30, 45, 123456789), ""},
+		{"RFC 3339 with fraction and offset", `"2024-03-10T12:30:45.5-05:00"`, utc(2024, 3, 10, 17, 30, 45, 500000000), ""},
+		{"space separated with zone", `"2024-03-10 12:30:45Z"`, utc(2024, 3, 10, 12, 30, 45, 0), ""},
+		{"space separated with offset", `"2024-03-10 12:30:45+01:00"`, utc(2024, 3, 10, 11, 30, 45, 0), ""},
+		{"no zone", `"2024-03-10T12:30:45"`, utc(2024, 3, 10, 12, 30, 45, 0), ""},
+		{"space separated without zone", `"2024-03-10 12:30:45"`, utc(2024, 3, 10, 12, 30, 45, 0), ""},
+		{"date only", `"2024-03-10"`, utc(2024, 3, 10, 0, 0, 0, 0), ""},
+		{"surrounding spaces", `" 2024-03-10T12:30:45Z "`, utc(2024, 3, 10, 12, 30, 45, 0), ""},
+		{"unparseable", `"yesterday"`, time.Time{}, "yesterday"},
+		{"invalid date", `"2024-02-30"`, time.Time{}, "2024-02-30"},
+		{"number", `1710073845`, time.Time{}, "1710073845"},
+		{"empty", `""`, time.Time{}, ""},
+		{"null", `null`, time.Time{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a Article
+			if err := json.Unmarshal([]byte(`{"publishedAt":`+tt.value+`}`), &a); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if !a.PublishedAt.Equal(tt.want) || a.PublishedAt.Location() != time.UTC {
+				t.Errorf("PublishedAt = %v, want %v in UTC", a.PublishedAt, tt.want)
+			}
+			if a.PublishedAtRaw != tt.wantRaw {
+				t.Errorf("PublishedAtRaw = %q, want %q", a.PublishedAtRaw, tt.wantRaw)
+			}
+		})
+	}
+
+	var a Article
+	if err := json.Unmarshal([]byte(`{"title":"no date"}`), &a); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !a.PublishedAt.IsZero() || a.PublishedAtRaw != "" {
+		t.Errorf("absent publishedAt decoded as %v / %q", a.PublishedAt, a.PublishedAtRaw)
+	}
+}
+
+// canonicalJSON decodes data into generic values, so that documents can be
+// compared regardless of key order and formatting.
+func canonicalJSON(t *testing.T, data []byte) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("invalid JSON %s: %v", data, err)
+	}
+	return v
+}
+
+func TestArticleRoundTrip(t *testing.T) {
+	data, err := os.ReadFile("testdata/articles.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var corpus []struct {
+		Name string          `json:"name"`
+		In   json.RawMessage `json:"in"`
+		Out  json.RawMessage `json:"out"` // when it differs from In
+	}
+	if err := json.Unmarshal(data, &corpus); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range corpus {
+		t.Run(tt.Name, func(t *testing.T) {
+			want := tt.Out
+			if want == nil {
+				want = tt.In
+			}
+
+			var a Article
+			if err := json.Unmarshal(tt.In, &a); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			got, err := json.Marshal(a)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if !reflect.DeepEqual(canonicalJSON(t, got), canonicalJSON(t, want)) {
+				t.Errorf("re-encoded as %s, want %s", got, want)
+			}
+
+			// A second round trip gives the same bytes
+			var again Article
+			if err := json.Unmarshal(got, &again); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if twice, _ := json.Marshal(again); string(twice) != string(got) {
+				t.Errorf("second round trip gave %s, want %s", twice, got)
+			}
+		})
+	}
+}
+
+func TestPublishedAtRawChanged(t *testing.T) {
+	var a Article
+	if err := json.Unmarshal([]byte(`{"publishedAt":12345}`), &a); err != nil {
+		t.Fatal(err)
+	}
+
+	// Once changed, PublishedAtRaw is written instead of the original token
+	a.PublishedAtRaw = "last week"
+	if got, _ := json.Marshal(a); string(got) != `{"publishedAt":"last week"}` {
+		t.Errorf("Marshal = %s, want the new PublishedAtRaw", got)
+	}
+	a.PublishedAtRaw = ""
+	if got, _ := json.Marshal(a); string(got) != `{}` {
+		t.Errorf("Marshal = %s, want publishedAt omitted", got)
+	}
+	a.PublishedAt = testTime
+	if got, _ := json.Marshal(a); string(got) != `{"publishedAt":"2024-03-10T12:00:00Z"}` {
+		t.Errorf("Marshal = %s, want PublishedAt", got)
+	}
+}
+
+func TestDecodedArticleEqualsLiteral(t *testing.T) {
+	var decoded Article
+	if err := json.Unmarshal([]byte(`{"title":"x","url":"https://example.com","publishedAt":"2024-03-10T12:00:00Z",`+
+		`"source":{"name":"Example","url":"https://example.com"}}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	want := Article{Title: "x", URL: "https://example.com", PublishedAt: testTime}
+	want.Source.Name = "Example"
+	want.Source.URL = "https://example.com"
+	if decoded != want || !reflect.DeepEqual(decoded, want) {
+		t.Errorf("decoded %#v, want it equal to %#v", decoded, want)
+	}
+
+	// Fields present with an empty value are remembered by value: the
+	// article differs from a literal without them, but decoding the same
+	// JSON twice gives equal articles; see the README
+	body := []byte(`{"title":"x","content":"","source":{"name":""}}`)
+	var again Article
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(body, &again); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != again {
+		t.Errorf("decoding %s twice gave %#v and %#v, want them equal", body, decoded, again)
+	}
+	if decoded == (Article{Title: "x"}) {
+		t.Error("an article with an empty field present equals the literal without it")
+	}
+	if decoded.extra != nil {
+		t.Errorf("empty fields were stored behind the pointer: %#v", decoded.extra)
+	}
+}
+
+func TestUnknownFields(t *testing.T) {
+	body := `{"totalArticles":1,"articles":[{"title":"t","Title":"shadowed","author":"Jane","paywall":{"free":3}}]}`
+
+	server, _ := recordingServer(t, body)
+	resp, err := newTestClient(t, server.URL, WithUnknownFields()).Search(nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	unknown := resp.Articles[0].Unknown()
+	if len(unknown) != 2 || string(unknown["author"]) != `"Jane"` || string(unknown["paywall"]) != `{"free":3}` {
+		t.Errorf("Unknown() = %s, want author and paywall", unknown)
+	}
+
+	resp, err = newTestClient(t, server.URL).Search(nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if unknown := resp.Articles[0].Unknown(); unknown != nil {
+		t.Errorf("Unknown() = %s without WithUnknownFields, want nil", unknown)
+	}
+}
+
+func TestSetUnknown(t *testing.T) {
+	var a Article
+	a.SetUnknown("author", json.RawMessage(`"Jane"`))
+	a.SetUnknown("title", json.RawMessage(`"ignored"`))
+	copied := a
+
+	a.SetUnknown("author", json.RawMessage(`"John"`))
+	a.SetUnknown("paywall", json.RawMessage(`true`))
+	if got := string(copied.Unknown()["author"]); got != `"Jane"` {
+		t.Errorf("copy sees author %s after SetUnknown on the original, want \"Jane\"", got)
+	}
+	if got := a.Unknown(); len(got) != 2 || string(got["author"]) != `"John"` {
+		t.Errorf("Unknown() = %s, want author John and paywall", got)
+	}
+
+	// The returned map is a copy
+	a.Unknown()["author"] = json.RawMessage(`"changed"`)
+	if got := string(a.Unknown()["author"]); got != `"John"` {
+		t.Errorf("modifying the result of Unknown changed the article: author %s", got)
+	}
+
+	a.SetUnknown("author", nil)
+	a.SetUnknown("paywall", nil)
+	if a.Unknown() != nil || a != (Article{}) {
+		t.Errorf("removing every unknown field left %+v, want the zero Article", a)
+	}
+}
+
+func TestArticleComparable(t *testing.T) {
+	a := Article{Title: "t", URL: "https://example.com"}
+	b := a
+	seen := map[Article]bool{a: true}
+	if a != b || !seen[b] {
+		t.Error("copies of an article are not equal")
+	}
+}
+
+// BenchmarkDecodeUnknownFields measures the cost of WithUnknownFields on a
+// 100-article response.
+func BenchmarkDecodeUnknownFields(b *testing.B) {
+	body := fatResponseBody(100, 2000)
+	for _, bm := range []struct {
+		name string
+		opts decodeOptions
+	}{
+		{"default", decodeOptions{}},
+		{"capture", decodeOptions{captureUnknown: true}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(body)))
+			for i := 0; i < b.N; i++ {
+				var resp SearchResponse
+				if err := resp.decode(body, bm.opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
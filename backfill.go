@@ -0,0 +1,77 @@
+package allnewsapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// backfillMax is the number of search results scanned for the article.
+const backfillMax = 10
+
+// BackfillContent fills in the Content of articles fetched without it or
+// with truncated content, with at most concurrency requests in flight. Each
+// article is looked up with a search for its exact title with Content set,
+// and matched to the results by canonical URL. Articles whose ContentStatus
+// is ContentFull are not looked up.
+//
+// The returned slice is a copy of articles. Articles whose content could not
+// be recovered are returned unchanged and reported in a *MultiError keyed by
+// their index, wrapping ErrContentUnavailable when the API did not return
+// the full content; the other articles are still filled in.
+func (c *Client) BackfillContent(ctx context.Context, articles []Article, concurrency int) ([]Article, error) {
+	filled := append([]Article(nil), articles...)
+	err := runConcurrent(ctx, len(filled), concurrency, false, func(ctx context.Context, i int) error {
+		if filled[i].ContentStatus().State == ContentFull {
+			return nil
+		}
+		content, err := c.lookupContent(ctx, filled[i])
+		if err != nil {
+			return err
+		}
+		filled[i].Content = content
+		return nil
+	})
+	return filled, err
+}
+
+// lookupContent searches for a and returns its content.
+func (c *Client) lookupContent(ctx context.Context, a Article) (string, error) {
+	if a.Title == "" || a.URL == "" {
+		return "", errors.New("article needs a title and a URL to be looked up")
+	}
+
+	options := &SearchOptions{
+		Query:      phrase(a.Title),
+		Attributes: []string{"title"},
+		Content:    Bool(true),
+		Max:        backfillMax,
+	}
+	if a.Lang != "" {
+		options.Lang = []string{a.Lang}
+	}
+
+	resp, err := c.SearchContext(ctx, options)
+	if err != nil {
+		return "", err
+	}
+
+	want := CanonicalURL(a.URL)
+	for _, found := range resp.Articles {
+		if CanonicalURL(found.URL) != want {
+			continue
+		}
+		if state := found.ContentStatus().State; state != ContentFull {
+			return "", fmt.Errorf("%w: %s content for %s", ErrContentUnavailable, state, a.URL)
+		}
+		return found.Content, nil
+	}
+	return "", fmt.Errorf("%w: %s not found in search results", ErrContentUnavailable, a.URL)
+}
+
+// phrase returns an exact phrase query for s. The API has no escape for
+// quotes inside a phrase, so they are removed.
+func phrase(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "") + `"`
+}
@@ -0,0 +1,194 @@
+package allnewsapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+// backfillServer answers searches for an exact title with the articles of
+// its fixtures, keyed by title without quotes, once hold releases the
+// request. Titles without fixtures get a 500. It records the queries and the
+// highest number of requests in flight.
+type backfillServer struct {
+	log         requestLog
+	inflight    int32
+	maxInflight int32
+}
+
+func newBackfillServer(t *testing.T, hold *barrier, fixtures map[string][]Article) (*backfillServer, string) {
+	s := &backfillServer{}
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		s.log.add(r)
+		n := atomic.AddInt32(&s.inflight, 1)
+		defer atomic.AddInt32(&s.inflight, -1)
+		for {
+			max := atomic.LoadInt32(&s.maxInflight)
+			if n <= max || atomic.CompareAndSwapInt32(&s.maxInflight, max, n) {
+				break
+			}
+		}
+		hold.wait()
+
+		title, quoted := unquotePhrase(r.URL.Query().Get("q"))
+		articles, ok := fixtures[title]
+		if !quoted || !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"message":"no fixture"}`)
+			return
+		}
+		json.NewEncoder(w).Encode(&SearchResponse{TotalArticles: int64(len(articles)), Articles: articles})
+	})
+	return s, server.URL
+}
+
+// unquotePhrase returns the text of an exact phrase query and whether q is
+// one.
+func unquotePhrase(q string) (string, bool) {
+	if len(q) < 2 || q[0] != '"' || q[len(q)-1] != '"' {
+		return "", false
+	}
+	return q[1 : len(q)-1], true
+}
+
+func TestBackfillContentConcurrencyBound(t *testing.T) {
+	articles := testArticles(12)
+	fixtures := make(map[string][]Article)
+	for i := range articles {
+		articles[i].Content = "Beginning… [+500 chars]"
+		full := articles[i]
+		full.Content = "Full content of " + full.Title
+		fixtures[full.Title] = []Article{full}
+	}
+
+	for _, concurrency := range []int{1, 3, 20} {
+		t.Run(fmt.Sprint(concurrency), func(t *testing.T) {
+			// Requests are answered in groups of want, so that many are
+			// in flight at once
+			want := int32(concurrency)
+			if want > 12 {
+				want = 12
+			}
+			s, url := newBackfillServer(t, newBarrier(int(want)), fixtures)
+			filled, err := newTestClient(t, url).BackfillContent(context.Background(), articles, concurrency)
+			if err != nil {
+				t.Fatalf("BackfillContent: %v", err)
+			}
+			for i, a := range filled {
+				if a.Content != "Full content of "+a.Title {
+					t.Errorf("article %d content = %q, want the full content", i, a.Content)
+				}
+			}
+
+			requests, max := len(s.log.all()), atomic.LoadInt32(&s.maxInflight)
+			if max != want || requests != 12 {
+				t.Errorf("%d requests with at most %d in flight, want 12 with %d", requests, max, want)
+			}
+		})
+	}
+}
+
+func TestBackfillContentPartialFailure(t *testing.T) {
+	truncated := "Beginning… [+500 chars]"
+	articles := []Article{
+		{Title: "complete", URL: "https://example.com/complete", Content: "Already complete."},
+		{Title: "found", URL: "https://example.com/found", Content: truncated, Lang: "fr"},
+		{Title: "still truncated", URL: "https://example.com/truncated", Content: truncated},
+		{Title: "not found", URL: "https://example.com/missing"},
+		{Title: "server error", URL: "https://example.com/error", Content: truncated},
+		{Title: "no url", Content: truncated},
+	}
+	fixtures := map[string][]Article{
+		// The result is matched by canonical URL, not by position
+		"found": {
+			{Title: "found", URL: "https://example.com/other", Content: "Another article."},
+			{Title: "found", URL: "https://www.example.com/found/?utm_source=x", Content: "Found in full."},
+		},
+		"still truncated": {{Title: "still truncated", URL: "https://example.com/truncated", Content: truncated}},
+		"not found":       {{Title: "not found", URL: "https://example.com/elsewhere", Content: "Elsewhere."}},
+	}
+	s, url := newBackfillServer(t, nil, fixtures)
+
+	filled, err := newTestClient(t, url).BackfillContent(context.Background(), articles, 3)
+
+	var multi *MultiError
+	if !errors.As(err, &multi) {
+		t.Fatalf("BackfillContent error = %v, want a *MultiError", err)
+	}
+	if multi.Total != len(articles) || len(multi.Failed) != 4 {
+		t.Errorf("MultiError = %v, want 4 of %d failed", multi, len(articles))
+	}
+	for _, i := range []int{2, 3} {
+		if !errors.Is(multi.Failed[i], ErrContentUnavailable) {
+			t.Errorf("article %d error = %v, want ErrContentUnavailable", i, multi.Failed[i])
+		}
+	}
+	var apiErr *APIError
+	if !errors.As(multi.Failed[4], &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("article 4 error = %v, want the 500 APIError", multi.Failed[4])
+	}
+	if multi.Failed[5] == nil {
+		t.Error("article 5 without a URL did not fail")
+	}
+
+	if filled[1].Content != "Found in full." {
+		t.Errorf("article 1 content = %q, want the content of the matching result", filled[1].Content)
+	}
+	for _, i := range []int{0, 2, 3, 4, 5} {
+		if filled[i] != articles[i] {
+			t.Errorf("article %d = %+v, want it unchanged", i, filled[i])
+		}
+	}
+	if articles[1].Content != truncated {
+		t.Error("BackfillContent modified its input")
+	}
+
+	// Complete articles and those without a URL are not looked up
+	requests := s.log.all()
+	if len(requests) != 4 {
+		t.Fatalf("sent %d requests, want 4", len(requests))
+	}
+	for _, r := range requests {
+		q := r.URL.Query()
+		if q.Get("attributes") != "title" || q.Get("content") != "true" || q.Get("max") != "10" {
+			t.Errorf("request %s, want an exact title search with content", r.URL.RawQuery)
+		}
+		if title, _ := unquotePhrase(q.Get("q")); title == "found" && q.Get("lang") != "fr" {
+			t.Errorf("request %s, want the lang of the article", r.URL.RawQuery)
+		}
+	}
+}
+
+func TestBackfillContentPhraseQuery(t *testing.T) {
+	tests := []struct {
+		title, query string
+	}{
+		{"Plain title", `"Plain title"`},
+		{"Café à Zürich: ça ouvre", `"Café à Zürich: ça ouvre"`},
+		{`Le "Grand Café" reopens`, `"Le Grand Café reopens"`},
+		{`Back\slash "quoted"`, `"Back\slash quoted"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			article := Article{Title: tt.title, URL: "https://example.com/a", Content: "Beginning… [+500 chars]"}
+			full := article
+			full.Content = "Full content."
+			s, url := newBackfillServer(t, nil, map[string][]Article{tt.query[1 : len(tt.query)-1]: {full}})
+
+			filled, err := newTestClient(t, url).BackfillContent(context.Background(), []Article{article}, 1)
+			if err != nil {
+				t.Fatalf("BackfillContent: %v", err)
+			}
+			if got := s.log.all()[0].URL.Query().Get("q"); got != tt.query {
+				t.Errorf("query = %s, want %s", got, tt.query)
+			}
+			if filled[0].Content != "Full content." {
+				t.Errorf("content = %q, want the full content", filled[0].Content)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package allnewsapi
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Default backoff used between retries.
+const (
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay  = 10 * time.Second
+)
+
+// BackoffPolicy decides how long to wait before retrying a failed request.
+// NextDelay is called with the number of the upcoming retry (1 for the first
+// retry), the error of the failed attempt, and its response when one was
+// received (the body is already consumed). Returning false stops retrying.
+//
+// NextDelay is only consulted for errors that IsRetryable accepts, and may
+// be called concurrently.
+type BackoffPolicy interface {
+	NextDelay(attempt int, lastErr error, resp *http.Response) (time.Duration, bool)
+}
+
+// WithBackoffPolicy sets the policy used to wait between retries. It has no
+// effect unless retries are enabled with WithRetry or WithRetryBudget. A nil
+// policy keeps the default Exponential policy.
+func WithBackoffPolicy(p BackoffPolicy) ClientOption {
+	return func(c *Client) {
+		if p != nil {
+			c.backoff = p
+		}
+	}
+}
+
+// Exponential doubles the delay after every retry, starting at Base and
+// capped at Max. Zero values default to 500ms and 10s.
+type Exponential struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// NextDelay implements BackoffPolicy.
+func (e Exponential) NextDelay(attempt int, _ error, _ *http.Response) (time.Duration, bool) {
+	return exponentialDelay(e.Base, e.Max, attempt), true
+}
+
+// ExponentialWithJitter waits a random duration between zero and the delay
+// Exponential would use ("full jitter"), which spreads out retries from many
+// clients failing at once.
+type ExponentialWithJitter struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// NextDelay implements BackoffPolicy.
+func (e ExponentialWithJitter) NextDelay(attempt int, _ error, _ *http.Response) (time.Duration, bool) {
+	delay := exponentialDelay(e.Base, e.Max, attempt)
+	if delay <= 0 {
+		return 0, true
+	}
+	return time.Duration(rand.Int63n(int64(delay) + 1)), true
+}
+
+// Constant waits the same Delay before every retry.
+type Constant struct {
+	Delay time.Duration
+}
+
+// NextDelay implements BackoffPolicy.
+func (c Constant) NextDelay(int, error, *http.Response) (time.Duration, bool) {
+	return c.Delay, true
+}
+
+// RetryAfter wraps a policy to honor the Retry-After header of 429 and 503
+// responses, in seconds or as an HTTP date. Responses without the header use
+// the wrapped policy.
+func RetryAfter(p BackoffPolicy) BackoffPolicy {
+	return retryAfter{next: p}
+}
+
+type retryAfter struct {
+	next  BackoffPolicy
+	clock Clock // nil outside of a client
+}
+
+func (r retryAfter) withClock(clock Clock) BackoffPolicy {
+	r.clock = clock
+	return r
+}
+
+func (r retryAfter) NextDelay(attempt int, lastErr error, resp *http.Response) (time.Duration, bool) {
+	clock := r.clock
+	if clock == nil {
+		clock = realClock{}
+	}
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), clock.Now()); ok {
+			return delay, true
+		}
+	}
+	return r.next.NextDelay(attempt, lastErr, resp)
+}
+
+// parseRetryAfter parses a Retry-After header value relative to now.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if delay := at.Sub(now); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// exponentialDelay returns base * 2^(attempt-1), capped at maxDelay.
+func exponentialDelay(base, maxDelay time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		base = defaultRetryBaseDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultRetryMaxDelay
+	}
+
+	delay := base
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
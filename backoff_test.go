@@ -0,0 +1,204 @@
+package allnewsapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	allnewsapi "github.com/AllNewsAPI/go-sdk"
+	"github.com/AllNewsAPI/go-sdk/allnewsapitest"
+)
+
+// delays returns the first n delays of p.
+func delays(p allnewsapi.BackoffPolicy, n int) []time.Duration {
+	var out []time.Duration
+	for attempt := 1; attempt <= n; attempt++ {
+		d, ok := p.NextDelay(attempt, errors.New("failed"), nil)
+		if !ok {
+			break
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+func TestBackoffSequences(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name   string
+		policy allnewsapi.BackoffPolicy
+		want   []time.Duration
+	}{
+		{"exponential defaults", allnewsapi.Exponential{},
+			[]time.Duration{500 * ms, time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}},
+		{"exponential custom", allnewsapi.Exponential{Base: 100 * ms, Max: time.Second},
+			[]time.Duration{100 * ms, 200 * ms, 400 * ms, 800 * ms, time.Second, time.Second, time.Second}},
+		{"exponential base above max", allnewsapi.Exponential{Base: 3 * time.Second, Max: time.Second},
+			[]time.Duration{time.Second, time.Second, time.Second, time.Second, time.Second, time.Second, time.Second}},
+		{"constant", allnewsapi.Constant{Delay: 250 * ms},
+			[]time.Duration{250 * ms, 250 * ms, 250 * ms, 250 * ms, 250 * ms, 250 * ms, 250 * ms}},
+		{"constant zero", allnewsapi.Constant{},
+			[]time.Duration{0, 0, 0, 0, 0, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := delays(tt.policy, len(tt.want))
+			if len(got) != len(tt.want) {
+				t.Fatalf("delays = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("delays = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	// Large attempt numbers stay capped instead of overflowing
+	if d, _ := (allnewsapi.Exponential{}).NextDelay(1000, nil, nil); d != 10*time.Second {
+		t.Errorf("delay of attempt 1000 = %s, want 10s", d)
+	}
+}
+
+func TestExponentialWithJitterBounds(t *testing.T) {
+	policy := allnewsapi.ExponentialWithJitter{Base: 100 * time.Millisecond, Max: time.Second}
+	caps := delays(allnewsapi.Exponential{Base: 100 * time.Millisecond, Max: time.Second}, 6)
+
+	for attempt := 1; attempt <= len(caps); attempt++ {
+		seen := make(map[time.Duration]bool)
+		for i := 0; i < 200; i++ {
+			d, ok := policy.NextDelay(attempt, nil, nil)
+			if !ok || d < 0 || d > caps[attempt-1] {
+				t.Fatalf("attempt %d: delay %s (ok %v) outside [0, %s]", attempt, d, ok, caps[attempt-1])
+			}
+			seen[d] = true
+		}
+		if len(seen) < 2 {
+			t.Errorf("attempt %d: 200 delays took a single value, want jitter", attempt)
+		}
+	}
+}
+
+func TestRetryAfterPolicy(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+	}{
+		{"seconds", "7", 7 * time.Second},
+		{"zero seconds", "0", 0},
+		{"HTTP date", now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{"past HTTP date", now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"missing", "", 3 * time.Second},
+		{"invalid", "soon", 3 * time.Second},
+		{"negative", "-5", 3 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests == 1 {
+					if tt.header != "" {
+						w.Header().Set("Retry-After", tt.header)
+					}
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				w.Write([]byte(okBody))
+			}))
+			defer server.Close()
+
+			clock := allnewsapitest.NewFakeClock(now)
+			client := newClient(t, server.URL, allnewsapi.WithClock(clock), allnewsapi.WithRetry(2),
+				allnewsapi.WithBackoffPolicy(allnewsapi.RetryAfter(allnewsapi.Constant{Delay: 3 * time.Second})))
+
+			var waits []time.Duration
+			if tt.want > 0 {
+				waits = append(waits, tt.want)
+			}
+			if err := searchWaiting(t, context.Background(), client, clock, waits...); err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			if elapsed := clock.Now().Sub(now); elapsed != tt.want {
+				t.Errorf("waited %s, want %s", elapsed, tt.want)
+			}
+		})
+	}
+}
+
+// recordingPolicy is a user-defined policy recording its calls. It stops
+// retrying after stopAfter retries.
+type recordingPolicy struct {
+	mu        sync.Mutex
+	attempts  []int
+	statuses  []int
+	stopAfter int
+}
+
+func (p *recordingPolicy) NextDelay(attempt int, lastErr error, resp *http.Response) (time.Duration, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.attempts = append(p.attempts, attempt)
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	p.statuses = append(p.statuses, status)
+	return 0, attempt < p.stopAfter
+}
+
+func TestCustomBackoffPolicy(t *testing.T) {
+	server, requests := unavailableServer(t)
+	policy := &recordingPolicy{stopAfter: 2}
+	client := newClient(t, server.URL, allnewsapi.WithRetry(10), allnewsapi.WithBackoffPolicy(policy))
+
+	_, err := client.Search(nil)
+	var apiErr *allnewsapi.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Search error = %v, want a 503 APIError", err)
+	}
+
+	// The policy allowed one retry and refused the second
+	if n := atomic.LoadInt32(requests); n != 2 {
+		t.Errorf("sent %d requests, want 2", n)
+	}
+	if len(policy.attempts) != 2 || policy.attempts[0] != 1 || policy.attempts[1] != 2 {
+		t.Errorf("policy called with attempts %v, want [1 2]", policy.attempts)
+	}
+	for _, status := range policy.statuses {
+		if status != http.StatusServiceUnavailable {
+			t.Errorf("policy saw status %d, want the 503 response", status)
+		}
+	}
+}
+
+func TestNilBackoffPolicyKeepsDefault(t *testing.T) {
+	server, requests := unavailableServer(t)
+	clock := allnewsapitest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	client := newClient(t, server.URL, allnewsapi.WithClock(clock), allnewsapi.WithRetry(2), allnewsapi.WithBackoffPolicy(nil))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Search(nil)
+		done <- err
+	}()
+
+	// The default policy waits 500ms before the first retry
+	clock.BlockUntil(1)
+	clock.Advance(499 * time.Millisecond)
+	if clock.Waiters() != 1 {
+		t.Fatal("retried before 500ms")
+	}
+	clock.Advance(time.Millisecond)
+	<-done
+	if n := atomic.LoadInt32(requests); n != 2 {
+		t.Errorf("sent %d requests, want 2", n)
+	}
+}
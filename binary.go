@@ -0,0 +1,325 @@
+package allnewsapi
+
+import (
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// binaryVersion is the version of the binary encoding of Article and
+// SearchResponse. The encoding is a version byte followed by tagged,
+// length-prefixed fields; decoders skip tags they do not know, so fields can
+// be added without breaking older readers.
+const binaryVersion = 1
+
+// Field tags of the binary Article encoding. Tags must never be reused.
+const (
+	tagTitle = iota + 1
+	tagDescription
+	tagCategory
+	tagContent
+	tagCountry
+	tagRegion
+	tagLang
+	tagSentiment
+	tagURL
+	tagImage
+	tagPublishedAt
+	tagPublishedAtRaw
+	tagSourceName
+	tagSourceURL
+	tagPresent
+	tagUnknown
+	tagPublishedAtToken
+)
+
+// Field tags of the binary SearchResponse encoding.
+const (
+	tagTotalArticles = iota + 1
+	tagCurrentPage
+	tagNextPage
+	tagPrevPage
+	tagTotalPages
+	tagArticle
+	tagRequestID
+)
+
+var registerGobOnce sync.Once
+
+// RegisterGobTypes registers Article and SearchResponse with encoding/gob,
+// which is needed to send them as interface values. Both types encode
+// themselves with MarshalBinary, which gob uses automatically; pass
+// SearchResponse to gob as a pointer. It is safe to call more than once.
+func RegisterGobTypes() {
+	registerGobOnce.Do(func() {
+		gob.Register(Article{})
+		gob.Register(&SearchResponse{})
+	})
+}
+
+// binaryWriter appends tagged fields to a buffer.
+type binaryWriter struct {
+	buf []byte
+}
+
+func newBinaryWriter() *binaryWriter {
+	return &binaryWriter{buf: []byte{binaryVersion}}
+}
+
+func (w *binaryWriter) uvarint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	w.buf = append(w.buf, tmp[:binary.PutUvarint(tmp[:], v)]...)
+}
+
+func (w *binaryWriter) field(tag uint64, value []byte) {
+	w.uvarint(tag)
+	w.uvarint(uint64(len(value)))
+	w.buf = append(w.buf, value...)
+}
+
+// string writes a string field when it is not empty.
+func (w *binaryWriter) string(tag uint64, s string) {
+	if s != "" {
+		w.field(tag, []byte(s))
+	}
+}
+
+// int writes a signed integer field.
+func (w *binaryWriter) int(tag uint64, v int64) {
+	var tmp [binary.MaxVarintLen64]byte
+	w.field(tag, tmp[:binary.PutVarint(tmp[:], v)])
+}
+
+// readVersioned checks the version byte of data and calls fn for each of
+// the fields that follow it.
+func readVersioned(data []byte, fn func(tag uint64, value []byte) error) error {
+	if len(data) == 0 {
+		return errors.New("empty binary data")
+	}
+	if data[0] != binaryVersion {
+		return fmt.Errorf("unsupported binary version %d", data[0])
+	}
+	return readFields(data[1:], fn)
+}
+
+// readFields calls fn for each field of data.
+func readFields(data []byte, fn func(tag uint64, value []byte) error) error {
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errors.New("malformed binary data: bad tag")
+		}
+		data = data[n:]
+		size, n := binary.Uvarint(data)
+		if n <= 0 || size > uint64(len(data)-n) {
+			return errors.New("malformed binary data: bad length")
+		}
+		data = data[n:]
+		if err := fn(tag, data[:size]); err != nil {
+			return err
+		}
+		data = data[size:]
+	}
+	return nil
+}
+
+// readInt decodes a field written by binaryWriter.int.
+func readInt(value []byte) (int64, error) {
+	v, n := binary.Varint(value)
+	if n <= 0 || n != len(value) {
+		return 0, errors.New("malformed binary data: bad integer")
+	}
+	return v, nil
+}
+
+// MarshalBinary encodes the article in a compact, versioned binary format.
+func (a Article) MarshalBinary() ([]byte, error) {
+	w := newBinaryWriter()
+	w.string(tagTitle, a.Title)
+	w.string(tagDescription, a.Description)
+	w.string(tagCategory, a.Category)
+	w.string(tagContent, a.Content)
+	w.string(tagCountry, a.Country)
+	w.string(tagRegion, a.Region)
+	w.string(tagLang, a.Lang)
+	w.string(tagSentiment, a.Sentiment)
+	w.string(tagURL, a.URL)
+	w.string(tagImage, a.Image)
+	if !a.PublishedAt.IsZero() {
+		t, err := a.PublishedAt.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		w.field(tagPublishedAt, t)
+	}
+	w.string(tagPublishedAtRaw, a.PublishedAtRaw)
+	w.string(tagSourceName, a.Source.Name)
+	w.string(tagSourceURL, a.Source.URL)
+	if a.empty != 0 {
+		w.int(tagPresent, int64(a.empty))
+	}
+	if a.extra != nil && a.extra.publishedAt != nil && a.extra.rawFrom == a.PublishedAtRaw {
+		w.field(tagPublishedAtToken, a.extra.publishedAt)
+	}
+
+	unknown := a.Unknown()
+	names := make([]string, 0, len(unknown))
+	for name := range unknown {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		entry := &binaryWriter{}
+		entry.field(1, []byte(name))
+		entry.field(2, unknown[name])
+		w.field(tagUnknown, entry.buf)
+	}
+
+	return w.buf, nil
+}
+
+// UnmarshalBinary decodes an article encoded by MarshalBinary.
+func (a *Article) UnmarshalBinary(data []byte) error {
+	*a = Article{}
+	var e articleExtra
+	e.unknown = make(map[string]json.RawMessage)
+	defer func() {
+		// Only fields that are still empty can have been present empty
+		a.empty &= a.emptyFields()
+		e.rawFrom = a.PublishedAtRaw
+		a.updateExtra(func(extra *articleExtra) { *extra = e })
+	}()
+	return readVersioned(data, func(tag uint64, value []byte) error {
+		switch tag {
+		case tagTitle:
+			a.Title = string(value)
+		case tagDescription:
+			a.Description = string(value)
+		case tagCategory:
+			a.Category = string(value)
+		case tagContent:
+			a.Content = string(value)
+		case tagCountry:
+			a.Country = string(value)
+		case tagRegion:
+			a.Region = string(value)
+		case tagLang:
+			a.Lang = string(value)
+		case tagSentiment:
+			a.Sentiment = string(value)
+		case tagURL:
+			a.URL = string(value)
+		case tagImage:
+			a.Image = string(value)
+		case tagPublishedAt:
+			var t time.Time
+			if err := t.UnmarshalBinary(value); err != nil {
+				return err
+			}
+			a.PublishedAt = t
+		case tagPublishedAtRaw:
+			a.PublishedAtRaw = string(value)
+		case tagSourceName:
+			a.Source.Name = string(value)
+		case tagSourceURL:
+			a.Source.URL = string(value)
+		case tagPresent:
+			present, err := readInt(value)
+			if err != nil {
+				return err
+			}
+			a.empty = fieldSet(present)
+		case tagUnknown:
+			return readUnknown(value, e.unknown)
+		case tagPublishedAtToken:
+			e.publishedAt = append(json.RawMessage(nil), value...)
+		}
+		return nil
+	})
+}
+
+// readUnknown decodes an unknown field entry, which is a name and a raw JSON
+// value written without a version byte, into unknown.
+func readUnknown(entry []byte, unknown map[string]json.RawMessage) error {
+	var name string
+	var raw json.RawMessage
+	err := readFields(entry, func(tag uint64, value []byte) error {
+		switch tag {
+		case 1:
+			name = string(value)
+		case 2:
+			raw = append(json.RawMessage(nil), value...)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	unknown[name] = raw
+	return nil
+}
+
+// MarshalBinary encodes the response in a compact, versioned binary format.
+// DecodeReport, RateLimit and Meta are not encoded.
+func (r *SearchResponse) MarshalBinary() ([]byte, error) {
+	w := newBinaryWriter()
+	w.int(tagTotalArticles, r.TotalArticles)
+	w.int(tagCurrentPage, int64(r.CurrentPage))
+	if r.NextPage != nil {
+		w.int(tagNextPage, int64(*r.NextPage))
+	}
+	if r.PrevPage != nil {
+		w.int(tagPrevPage, int64(*r.PrevPage))
+	}
+	w.int(tagTotalPages, int64(r.TotalPages))
+	for _, a := range r.Articles {
+		data, err := a.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		w.field(tagArticle, data)
+	}
+	w.string(tagRequestID, r.RequestID)
+	return w.buf, nil
+}
+
+// UnmarshalBinary decodes a response encoded by MarshalBinary.
+func (r *SearchResponse) UnmarshalBinary(data []byte) error {
+	*r = SearchResponse{}
+	return readVersioned(data, func(tag uint64, value []byte) error {
+		switch tag {
+		case tagTotalArticles, tagCurrentPage, tagNextPage, tagPrevPage, tagTotalPages:
+			v, err := readInt(value)
+			if err != nil {
+				return err
+			}
+			n := int(v)
+			switch tag {
+			case tagTotalArticles:
+				r.TotalArticles = v
+			case tagCurrentPage:
+				r.CurrentPage = n
+			case tagNextPage:
+				r.NextPage = &n
+			case tagPrevPage:
+				r.PrevPage = &n
+			case tagTotalPages:
+				r.TotalPages = n
+			}
+		case tagArticle:
+			var a Article
+			if err := a.UnmarshalBinary(value); err != nil {
+				return err
+			}
+			r.Articles = append(r.Articles, a)
+		case tagRequestID:
+			r.RequestID = string(value)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,221 @@
+package allnewsapi
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// populatedArticle returns an article with every field set, including the
+// state kept by decoding: an empty field, an unparsed date and unknown
+// fields.
+func populatedArticle(t testing.TB) Article {
+	t.Helper()
+	var a Article
+	err := a.decode([]byte(`{"title":"Title","description":"Description","category":"business",`+
+		`"content":"","country":"us","region":"north-america","lang":"en","sentiment":"positive",`+
+		`"url":"https://news.example.com/1","image":"https://news.example.com/1.jpg",`+
+		`"publishedAt":12345,"source":{"name":"Example News","url":"https://news.example.com"},`+
+		`"author":"Reporter","paywall":{"type":"metered","free":3}}`), decodeOptions{captureUnknown: true})
+	if err != nil {
+		t.Fatalf("decoding fixture: %v", err)
+	}
+	return a
+}
+
+func TestArticleBinaryRoundTrip(t *testing.T) {
+	dated := testArticles(1)[0]
+	tests := []struct {
+		name    string
+		article Article
+	}{
+		{"zero", Article{}},
+		{"dated", dated},
+		{"populated", populatedArticle(t)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.article.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary: %v", err)
+			}
+			var got Article
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.article) {
+				t.Errorf("round trip gave %#v, want %#v", got, tt.article)
+			}
+
+			// The decoded article writes the same JSON as the original
+			want, _ := json.Marshal(tt.article)
+			if js, _ := json.Marshal(got); string(js) != string(want) {
+				t.Errorf("round trip re-encodes as %s, want %s", js, want)
+			}
+		})
+	}
+
+	// Articles without decoding state stay == to the original
+	data, _ := dated.MarshalBinary()
+	var got Article
+	if err := got.UnmarshalBinary(data); err != nil || got != dated {
+		t.Errorf("round trip gave %#v (error %v), want == %#v", got, err, dated)
+	}
+}
+
+func TestArticleBinaryStaleToken(t *testing.T) {
+	a := populatedArticle(t)
+	a.PublishedAtRaw = "yesterday"
+	data, err := a.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Article
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if js, _ := json.Marshal(got); !strings.Contains(string(js), `"publishedAt":"yesterday"`) {
+		t.Errorf("round trip re-encodes as %s, want the changed PublishedAtRaw", js)
+	}
+}
+
+func TestSearchResponseBinaryRoundTrip(t *testing.T) {
+	next, prev := 3, 1
+	tests := []struct {
+		name string
+		resp *SearchResponse
+	}{
+		{"zero", &SearchResponse{}},
+		{"populated", &SearchResponse{
+			TotalArticles: 1 << 40,
+			CurrentPage:   2,
+			NextPage:      &next,
+			PrevPage:      &prev,
+			TotalPages:    4,
+			Articles:      append(testArticles(3), populatedArticle(t), Article{}),
+			RequestID:     "req-1",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.resp.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary: %v", err)
+			}
+			var got SearchResponse
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary: %v", err)
+			}
+			if !reflect.DeepEqual(&got, tt.resp) {
+				t.Errorf("round trip gave %+v, want %+v", &got, tt.resp)
+			}
+		})
+	}
+}
+
+func TestBinaryGob(t *testing.T) {
+	RegisterGobTypes()
+	RegisterGobTypes()
+
+	want := []interface{}{populatedArticle(t), &SearchResponse{TotalArticles: 2, Articles: testArticles(2)}}
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	for _, v := range want {
+		if err := enc.Encode(&v); err != nil {
+			t.Fatalf("Encode: %v", err)
+		}
+	}
+	dec := gob.NewDecoder(&buf)
+	for _, w := range want {
+		var got interface{}
+		if err := dec.Decode(&got); err != nil {
+			t.Fatalf("Decode: %v", err)
+		}
+		if !reflect.DeepEqual(got, w) {
+			t.Errorf("gob gave %#v, want %#v", got, w)
+		}
+	}
+}
+
+func TestBinaryErrors(t *testing.T) {
+	valid, _ := Article{Title: "x"}.MarshalBinary()
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{"empty", nil, "empty binary data"},
+		{"future version", []byte{binaryVersion + 1}, "unsupported binary version 2"},
+		{"truncated", valid[:len(valid)-1], "bad length"},
+		{"bad tag", []byte{binaryVersion, 0x80}, "bad tag"},
+		{"bad integer", []byte{binaryVersion, tagPresent, 1, 0x80}, "bad integer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a Article
+			if err := a.UnmarshalBinary(tt.data); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("UnmarshalBinary error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Unknown tags are skipped
+	var a Article
+	data := append(append([]byte(nil), valid...), 99, 1, 'z')
+	if err := a.UnmarshalBinary(data); err != nil || a.Title != "x" {
+		t.Errorf("UnmarshalBinary with an unknown tag = %+v, %v, want the known fields", a, err)
+	}
+}
+
+// BenchmarkArticleEncoding compares the JSON and binary encodings of a
+// 100-article response with large content.
+func BenchmarkArticleEncoding(b *testing.B) {
+	var resp SearchResponse
+	if err := resp.decode(fatResponseBody(100, 2000), decodeOptions{}); err != nil {
+		b.Fatal(err)
+	}
+	jsonData, err := json.Marshal(&resp)
+	if err != nil {
+		b.Fatal(err)
+	}
+	binaryData, err := resp.MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, bm := range []struct {
+		name      string
+		data      []byte
+		marshal   func() ([]byte, error)
+		unmarshal func(data []byte) error
+	}{
+		{"json", jsonData,
+			func() ([]byte, error) { return json.Marshal(&resp) },
+			func(data []byte) error { var r SearchResponse; return json.Unmarshal(data, &r) }},
+		{"binary", binaryData,
+			resp.MarshalBinary,
+			func(data []byte) error { var r SearchResponse; return r.UnmarshalBinary(data) }},
+	} {
+		b.Run(bm.name+"/marshal", func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(bm.data)))
+			for i := 0; i < b.N; i++ {
+				if _, err := bm.marshal(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(bm.name+"/unmarshal", func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(bm.data)))
+			for i := 0; i < b.N; i++ {
+				if err := bm.unmarshal(bm.data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
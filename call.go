@@ -0,0 +1,40 @@
+package allnewsapi
+
+import "time"
+
+// CallOption configures a single call to the API.
+type CallOption func(*callConfig)
+
+// callConfig holds the per-call settings.
+type callConfig struct {
+	requestID string
+	timeout   time.Duration
+	dryRun    bool
+	apiKey    string
+}
+
+func newCallConfig(callOpts []CallOption) *callConfig {
+	cfg := &callConfig{}
+	for _, option := range callOpts {
+		option(cfg)
+	}
+	return cfg
+}
+
+// WithCallAPIKey sends the call with apiKey instead of the client API key,
+// for services making calls on behalf of several API accounts. The key only
+// applies to this call and is redacted from errors like the client key.
+func WithCallAPIKey(apiKey string) CallOption {
+	return func(cfg *callConfig) {
+		cfg.apiKey = apiKey
+	}
+}
+
+// WithRequestID sets the ID sent in the X-Request-ID header of the call,
+// instead of a generated one. Use it to correlate the call with requests in
+// your own system.
+func WithRequestID(id string) CallOption {
+	return func(cfg *callConfig) {
+		cfg.requestID = id
+	}
+}
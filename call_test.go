@@ -0,0 +1,84 @@
+package allnewsapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestCallAPIKeyConcurrent interleaves calls with different keys on one
+// client and checks that every request, including retries, carries the key
+// of its own call, and that errors and metadata do not reveal keys.
+func TestCallAPIKeyConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	attempts := make(map[string]int) // by query
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		q, key := r.URL.Query().Get("q"), r.URL.Query().Get("apikey")
+		mu.Lock()
+		attempts[q]++
+		first := attempts[q] == 1
+		mu.Unlock()
+
+		switch {
+		case first:
+			// Every call is retried once
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case strings.HasPrefix(key, "denied"):
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"invalid key"}`)
+		default:
+			fmt.Fprintf(w, `{"totalArticles":1,"articles":[{"title":%q}]}`, key+" "+q)
+		}
+	})
+	client := newTestClient(t, server.URL, WithRetry(2), WithBackoffPolicy(Constant{}))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				q := fmt.Sprintf("%d-%d", g, i)
+				var key string
+				var callOpts []CallOption
+				switch i % 3 {
+				case 0:
+					key = testAPIKey // the client key
+				case 1:
+					key = fmt.Sprintf("key-%d-%d", g, i)
+					callOpts = append(callOpts, WithCallAPIKey(key))
+				case 2:
+					key = fmt.Sprintf("denied-%d-%d", g, i)
+					callOpts = append(callOpts, WithCallAPIKey(key))
+				}
+
+				resp, err := client.Search(&SearchOptions{Query: q}, callOpts...)
+				if strings.HasPrefix(key, "denied") {
+					var apiErr *APIError
+					if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+						t.Errorf("call %s with %s error = %v, want a 401", q, key, err)
+						continue
+					}
+					if msg := err.Error(); strings.Contains(msg, key) || strings.Contains(msg, testAPIKey) {
+						t.Errorf("call %s error %q contains a key", q, msg)
+					}
+					continue
+				}
+				if err != nil {
+					t.Errorf("call %s: %v", q, err)
+					continue
+				}
+				if got := resp.Articles[0].Title; got != key+" "+q {
+					t.Errorf("call %s with %s was sent with %q", q, key, got)
+				}
+				if strings.Contains(resp.Meta.FinalURL, key) {
+					t.Errorf("call %s FinalURL %s does not redact its key", q, resp.Meta.FinalURL)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
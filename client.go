@@ -2,13 +2,10 @@
 package allnewsapi
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
-	"fmt"
-	"io"
+	"net"
 	"net/http"
-	"net/url"
-	"strings"
 	"time"
 )
 
@@ -17,33 +14,37 @@ type Client struct {
 	apiKey     string
 	baseURL    string
 	httpClient *http.Client
-}
-
-// Article represents a news article returned by the API.
-type Article struct {
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Category    string    `json:"category"`
-	Content     string    `json:"content"`
-	Country     string    `json:"country"`
-	Region      string    `json:"region"`
-	Lang        string    `json:"lang"`
-	Sentiment   string    `json:"sentiment"`
-	URL         string    `json:"url"`
-	Image       string    `json:"image"`
-	PublishedAt time.Time `json:"publishedAt"`
-	Source      struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"source"`
-}
-
-// SearchResponse represents the response from the search endpoint.
-type SearchResponse struct {
-	TotalArticles int       `json:"totalArticles"`
-	CurrentPage   int       `json:"currentPage"`
-	NextPage      *int       `json:"nextPage"`
-	Articles      []Article `json:"articles"`
+	defaults   *SearchOptions
+	lenient    bool
+	strict     bool
+
+	maxAttempts int
+	maxElapsed  time.Duration
+	backoff     BackoffPolicy
+	hedgeDelay  time.Duration
+	maxHedges   int
+	metrics     func(RequestMetrics)
+	trace       func(RequestTiming)
+	pacing      bool
+
+	baseHTTPClient *http.Client
+	transport      http.RoundTripper
+	timeout        *time.Duration
+	pool           *poolConfig
+	dialContext    func(ctx context.Context, network, addr string) (net.Conn, error)
+	resolver       *net.Resolver
+	networkFamily  string
+	redirectPolicy RedirectPolicy
+	compression    compressionMode
+
+	sharedTransport *http.Transport
+	ownsTransport   bool  // transport was created by buildHTTPClient
+	closed          int32 // set by Close, accessed atomically
+
+	captureUnknown bool
+	clock          Clock
+
+	presets presets
 }
 
 // ClientOption is a function that configures a Client.
@@ -59,7 +60,47 @@ func WithBaseURL(baseURL string) ClientOption {
 // WithTimeout sets a custom timeout for HTTP requests.
 func WithTimeout(timeout time.Duration) ClientOption {
 	return func(c *Client) {
-		c.httpClient.Timeout = timeout
+		c.timeout = &timeout
+	}
+}
+
+// WithDefaultSearchOptions sets options applied to every request. Per-call
+// options are merged over the defaults using SearchOptions.Merge: non-zero
+// scalar fields (Query, Max, Page, Content, dates, ...) override the default,
+// and non-nil slice fields (Lang, Country, ...) replace the default slice
+// rather than appending to it. Passing nil options to Search or Headlines uses
+// the defaults as is.
+func WithDefaultSearchOptions(defaults SearchOptions) ClientOption {
+	return func(c *Client) {
+		c.defaults = defaults.Clone()
+	}
+}
+
+// WithLenientDecoding makes the client skip articles that fail to decode
+// instead of failing the whole response. Skipped articles are listed in
+// SearchResponse.DecodeReport.
+func WithLenientDecoding() ClientOption {
+	return func(c *Client) {
+		c.lenient = true
+	}
+}
+
+// WithStrictDecoding makes the client reject responses containing fields
+// that are not part of the SDK models, with an error naming the field. It
+// is meant for contract tests and cannot be combined with
+// WithLenientDecoding.
+func WithStrictDecoding() ClientOption {
+	return func(c *Client) {
+		c.strict = true
+	}
+}
+
+// WithUnknownFields makes the client keep article fields not modeled by
+// Article, available through Article.Unknown. It is opt-in because it
+// decodes every article a second time.
+func WithUnknownFields() ClientOption {
+	return func(c *Client) {
+		c.captureUnknown = true
 	}
 }
 
@@ -72,9 +113,8 @@ func NewClient(apiKey string, options ...ClientOption) (*Client, error) {
 	client := &Client{
 		apiKey:  apiKey,
 		baseURL: "https://api.allnewsapi.com",
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		backoff: Exponential{},
+		clock:   realClock{},
 	}
 
 	// Apply options
@@ -82,259 +122,37 @@ func NewClient(apiKey string, options ...ClientOption) (*Client, error) {
 		option(client)
 	}
 
-	return client, nil
-}
-
-// SearchOptions contains all possible parameters for the search endpoint.
-type SearchOptions struct {
-	Query       string      // Search query
-	StartDate   interface{} // string or time.Time
-	EndDate     interface{} // string or time.Time
-	Content     *bool       // Whether to include full content
-	Lang        []string    // Languages to filter by
-	Country     []string    // Countries to filter by
-	Region      []string    // Regions to filter by
-	Category    []string    // Categories to filter by
-	Max         int         // Maximum number of results (1-100)
-	Attributes  []string    // Attributes to search in (title, description, content)
-	Page        int         // Page number for pagination
-	SortBy      string      // Sort by 'publishedAt' or 'relevance'
-	Publisher   []string    // Publishers to filter by
-	Format      string      // Response format (json, csv, xlsx)
-}
-
-// Search searches for news articles.
-func (c *Client) Search(options *SearchOptions) (*SearchResponse, error) {
-	params := url.Values{}
-
-	// Add API key
-	params.Add("apikey", c.apiKey)
-
-	// Add query parameters if provided
-	if options != nil {
-		if options.Query != "" {
-			params.Add("q", options.Query)
-		}
-
-		// Handle start date
-		if options.StartDate != nil {
-			var startDate string
-			switch v := options.StartDate.(type) {
-			case string:
-				startDate = v
-			case time.Time:
-				startDate = v.Format(time.RFC3339)
-			default:
-				return nil, errors.New("startDate must be string or time.Time")
-			}
-			params.Add("startDate", startDate)
-		}
-
-		// Handle end date
-		if options.EndDate != nil {
-			var endDate string
-			switch v := options.EndDate.(type) {
-			case string:
-				endDate = v
-			case time.Time:
-				endDate = v.Format(time.RFC3339)
-			default:
-				return nil, errors.New("endDate must be string or time.Time")
-			}
-			params.Add("endDate", endDate)
-		}
-
-		// Handle boolean content parameter
-		if options.Content != nil {
-			if *options.Content {
-				params.Add("content", "true")
-			} else {
-				params.Add("content", "false")
-			}
-		}
-
-		// Handle array parameters
-		if len(options.Lang) > 0 {
-			params.Add("lang", strings.Join(options.Lang, ","))
-		}
-		if len(options.Country) > 0 {
-			params.Add("country", strings.Join(options.Country, ","))
-		}
-		if len(options.Region) > 0 {
-			params.Add("region", strings.Join(options.Region, ","))
-		}
-		if len(options.Category) > 0 {
-			params.Add("category", strings.Join(options.Category, ","))
-		}
-		if len(options.Attributes) > 0 {
-			params.Add("attributes", strings.Join(options.Attributes, ","))
-		}
-		if len(options.Publisher) > 0 {
-			params.Add("publisher", strings.Join(options.Publisher, ","))
-		}
-
-		// Handle integer parameters
-		if options.Max > 0 {
-			params.Add("max", fmt.Sprintf("%d", options.Max))
-		}
-		if options.Page > 0 {
-			params.Add("page", fmt.Sprintf("%d", options.Page))
-		}
-
-		// Handle other string parameters
-		if options.SortBy != "" {
-			params.Add("sortby", options.SortBy)
-		}
-		if options.Format != "" {
-			params.Add("format", options.Format)
-		}
+	if cs, ok := client.backoff.(clockSetter); ok {
+		client.backoff = cs.withClock(client.clock)
 	}
 
-	// Build request URL
-	searchURL := fmt.Sprintf("%s/v1/search?%s", c.baseURL, params.Encode())
-
-	// Make the request
-	req, err := http.NewRequest("GET", searchURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+	if client.lenient && client.strict {
+		return nil, errors.New("WithLenientDecoding and WithStrictDecoding cannot be combined")
 	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
+	if err := client.buildHTTPClient(); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	// Check for error responses
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, body)
-	}
+	return client, nil
+}
 
-	// Parse the response
-	var searchResponse SearchResponse
-	err = json.NewDecoder(resp.Body).Decode(&searchResponse)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing response: %w", err)
-	}
+// Search searches for news articles.
+func (c *Client) Search(options *SearchOptions, callOpts ...CallOption) (*SearchResponse, error) {
+	return c.SearchContext(context.Background(), options, callOpts...)
+}
 
-	return &searchResponse, nil
+// SearchContext searches for news articles using the provided context.
+func (c *Client) SearchContext(ctx context.Context, options *SearchOptions, callOpts ...CallOption) (*SearchResponse, error) {
+	return c.query(ctx, searchEndpoint, options, callOpts)
 }
 
 // Headlines fetches news headlines.
-func (c *Client) Headlines(options *SearchOptions) (*SearchResponse, error) {
-	params := url.Values{}
-
-	// Add API key
-	params.Add("apikey", c.apiKey)
-
-	// Add query parameters if provided
-	if options != nil {
-		if options.Query != "" {
-			params.Add("q", options.Query)
-		}
-
-		// Handle start date
-		if options.StartDate != nil {
-			var startDate string
-			switch v := options.StartDate.(type) {
-			case string:
-				startDate = v
-			case time.Time:
-				startDate = v.Format(time.RFC3339)
-			default:
-				return nil, errors.New("startDate must be string or time.Time")
-			}
-			params.Add("startDate", startDate)
-		}
-
-		// Handle end date
-		if options.EndDate != nil {
-			var endDate string
-			switch v := options.EndDate.(type) {
-			case string:
-				endDate = v
-			case time.Time:
-				endDate = v.Format(time.RFC3339)
-			default:
-				return nil, errors.New("endDate must be string or time.Time")
-			}
-			params.Add("endDate", endDate)
-		}
-
-		// Handle boolean content parameter
-		if options.Content != nil {
-			if *options.Content {
-				params.Add("content", "true")
-			} else {
-				params.Add("content", "false")
-			}
-		}
-
-		// Handle array parameters
-		if len(options.Lang) > 0 {
-			params.Add("lang", strings.Join(options.Lang, ","))
-		}
-		if len(options.Country) > 0 {
-			params.Add("country", strings.Join(options.Country, ","))
-		}
-		if len(options.Region) > 0 {
-			params.Add("region", strings.Join(options.Region, ","))
-		}
-		if len(options.Category) > 0 {
-			params.Add("category", strings.Join(options.Category, ","))
-		}
-		if len(options.Attributes) > 0 {
-			params.Add("attributes", strings.Join(options.Attributes, ","))
-		}
-		if len(options.Publisher) > 0 {
-			params.Add("publisher", strings.Join(options.Publisher, ","))
-		}
-
-		// Handle integer parameters
-		if options.Max > 0 {
-			params.Add("max", fmt.Sprintf("%d", options.Max))
-		}
-		if options.Page > 0 {
-			params.Add("page", fmt.Sprintf("%d", options.Page))
-		}
-
-		// Handle other string parameters
-		if options.SortBy != "" {
-			params.Add("sortby", options.SortBy)
-		}
-		if options.Format != "" {
-			params.Add("format", options.Format)
-		}
-	}
-
-	// Build request URL
-	headlinesURL := fmt.Sprintf("%s/v1/headlines?%s", c.baseURL, params.Encode())
-
-	// Make the request
-	req, err := http.NewRequest("GET", headlinesURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check for error responses
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, body)
-	}
-
-	// Parse the response
-	var searchResponse SearchResponse
-	err = json.NewDecoder(resp.Body).Decode(&searchResponse)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing response: %w", err)
-	}
+func (c *Client) Headlines(options *SearchOptions, callOpts ...CallOption) (*SearchResponse, error) {
+	return c.HeadlinesContext(context.Background(), options, callOpts...)
+}
 
-	return &searchResponse, nil
+// HeadlinesContext fetches news headlines using the provided context.
+func (c *Client) HeadlinesContext(ctx context.Context, options *SearchOptions, callOpts ...CallOption) (*SearchResponse, error) {
+	return c.query(ctx, headlinesEndpoint, options, callOpts)
 }
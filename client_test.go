@@ -0,0 +1,101 @@
+package allnewsapi
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestDefaultSearchOptionsPrecedence(t *testing.T) {
+	defaults := SearchOptions{
+		Query:    "default query",
+		Lang:     []string{"en", "fr"},
+		Country:  []string{"us"},
+		Category: []string{"business"},
+		Max:      20,
+		SortBy:   "publishedAt",
+		Content:  Bool(true),
+	}
+
+	tests := []struct {
+		name    string
+		options *SearchOptions
+		want    url.Values
+	}{
+		{
+			name:    "nil options use the defaults",
+			options: nil,
+			want: url.Values{
+				"q": {"default query"}, "lang": {"en,fr"}, "country": {"us"}, "category": {"business"},
+				"max": {"20"}, "sortby": {"publishedAt"}, "content": {"true"},
+			},
+		},
+		{
+			name:    "scalars override",
+			options: &SearchOptions{Query: "bitcoin", Max: 5, SortBy: "relevance", Content: Bool(false)},
+			want: url.Values{
+				"q": {"bitcoin"}, "lang": {"en,fr"}, "country": {"us"}, "category": {"business"},
+				"max": {"5"}, "sortby": {"relevance"}, "content": {"false"},
+			},
+		},
+		{
+			name:    "slices replace rather than append",
+			options: &SearchOptions{Lang: []string{"de"}, Country: []string{"gb", "ie"}},
+			want: url.Values{
+				"q": {"default query"}, "lang": {"de"}, "country": {"gb,ie"}, "category": {"business"},
+				"max": {"20"}, "sortby": {"publishedAt"}, "content": {"true"},
+			},
+		},
+		{
+			name:    "empty slice clears the default",
+			options: &SearchOptions{Category: []string{}},
+			want: url.Values{
+				"q": {"default query"}, "lang": {"en,fr"}, "country": {"us"},
+				"max": {"20"}, "sortby": {"publishedAt"}, "content": {"true"},
+			},
+		},
+		{
+			name:    "zero scalars keep the default",
+			options: &SearchOptions{Query: "", Max: 0},
+			want: url.Values{
+				"q": {"default query"}, "lang": {"en,fr"}, "country": {"us"}, "category": {"business"},
+				"max": {"20"}, "sortby": {"publishedAt"}, "content": {"true"},
+			},
+		},
+	}
+
+	server, log := recordingServer(t, `{"totalArticles":0,"articles":[]}`)
+	client := newTestClient(t, server.URL, WithDefaultSearchOptions(defaults))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(log.all())
+			if _, err := client.Search(tt.options); err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			got := log.all()[before].URL.Query()
+			got.Del("apikey")
+			if got.Encode() != tt.want.Encode() {
+				t.Errorf("query = %s, want %s", got.Encode(), tt.want.Encode())
+			}
+		})
+	}
+}
+
+func TestDefaultSearchOptionsAreCopied(t *testing.T) {
+	defaults := SearchOptions{Lang: []string{"en"}}
+	server, log := recordingServer(t, `{"totalArticles":0,"articles":[]}`)
+	client := newTestClient(t, server.URL, WithDefaultSearchOptions(defaults))
+
+	// Neither the caller's defaults nor per-call options leak into the client
+	defaults.Lang[0] = "fr"
+	options := &SearchOptions{}
+	if _, err := client.Search(options); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if options.Lang != nil {
+		t.Errorf("Search modified the options: Lang = %v", options.Lang)
+	}
+	if got := log.all()[0].URL.Query().Get("lang"); got != "en" {
+		t.Errorf("lang = %q, want %q", got, "en")
+	}
+}
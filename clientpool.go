@@ -0,0 +1,88 @@
+package allnewsapi
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrClientClosed is returned by calls on a closed Client.
+var ErrClientClosed = errors.New("client is closed")
+
+// WithSharedTransport makes the client send requests through transport
+// as is, sharing its connection pool with every other client using it. The
+// client does not own the transport: Close does not close its connections.
+// It cannot be combined with WithTransport or with the options that
+// configure a transport, such as WithConnectionPool.
+func WithSharedTransport(transport *http.Transport) ClientOption {
+	return func(c *Client) {
+		c.sharedTransport = transport
+	}
+}
+
+// Close releases the resources of the client. Calls made after Close fail
+// with ErrClientClosed. The idle connections of the transport are closed
+// only when the client created the transport itself, that is when
+// WithConnectionPool, WithDialContext, WithResolver or WithNetworkFamily was
+// used; shared transports and transports passed in are left untouched.
+// Close is safe to call more than once.
+func (c *Client) Close() error {
+	if atomic.SwapInt32(&c.closed, 1) == 1 {
+		return nil
+	}
+	if c.ownsTransport {
+		if t, ok := c.httpClient.Transport.(*http.Transport); ok {
+			t.CloseIdleConnections()
+		}
+	}
+	return nil
+}
+
+// ClientPool creates clients that share one transport, and therefore one
+// connection pool, while keeping their own API keys, base URLs, retry
+// policies and other options. The pool owns the transport: closing a client
+// created by the pool does not affect the others, and Close on the pool
+// closes the shared connections.
+type ClientPool struct {
+	transport *http.Transport
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewClientPool returns a ClientPool sharing transport, or a clone of
+// http.DefaultTransport when transport is nil.
+func NewClientPool(transport *http.Transport) *ClientPool {
+	if transport == nil {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	return &ClientPool{transport: transport}
+}
+
+// NewClient creates a client using the shared transport. See NewClient for
+// the arguments.
+func (p *ClientPool) NewClient(apiKey string, options ...ClientOption) (*Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, errors.New("client pool is closed")
+	}
+	options = append(options[:len(options):len(options)], WithSharedTransport(p.transport))
+	return NewClient(apiKey, options...)
+}
+
+// Close closes the idle connections of the shared transport and prevents the
+// creation of new clients. Clients created before keep working, opening new
+// connections as needed.
+func (p *ClientPool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.closed {
+		p.closed = true
+		p.transport.CloseIdleConnections()
+	}
+	return nil
+}
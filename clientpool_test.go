@@ -0,0 +1,116 @@
+package allnewsapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// connCountingServer starts a server answering every request with an empty
+// response and counting the connections it accepts.
+func connCountingServer(t *testing.T) (url string, conns *int32) {
+	conns = new(int32)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"totalArticles":0,"articles":[]}`))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(conns, 1)
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+	return server.URL, conns
+}
+
+// searchReused searches with client and reports whether the request went
+// over a reused connection.
+func searchReused(t *testing.T, client *Client) bool {
+	t.Helper()
+	var reused bool
+	ctx := httptrace.WithClientTrace(context.Background(), &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) { reused = info.Reused },
+	})
+	if _, err := client.SearchContext(ctx, nil); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	return reused
+}
+
+func TestClientPoolReusesConnections(t *testing.T) {
+	url, conns := connCountingServer(t)
+	pool := NewClientPool(nil)
+	defer pool.Close()
+
+	a, err := pool.NewClient("key-a", WithBaseURL(url))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := pool.NewClient("key-b", WithBaseURL(url), WithRetry(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if searchReused(t, a) {
+		t.Error("the first request reused a connection")
+	}
+	for i := 0; i < 3; i++ {
+		if !searchReused(t, b) || !searchReused(t, a) {
+			t.Fatal("pooled clients did not reuse the shared connection")
+		}
+	}
+
+	// Closing one client leaves the shared connections to the others
+	a.Close()
+	if !searchReused(t, b) {
+		t.Error("closing a pooled client closed the shared connections")
+	}
+	if n := atomic.LoadInt32(conns); n != 1 {
+		t.Errorf("server accepted %d connections, want 1", n)
+	}
+
+	// Closing the pool closes the idle connections, but clients keep working
+	pool.Close()
+	if searchReused(t, b) {
+		t.Error("request after pool Close reused a connection")
+	}
+	if n := atomic.LoadInt32(conns); n != 2 {
+		t.Errorf("server accepted %d connections, want 2", n)
+	}
+	if _, err := pool.NewClient("key-c"); err == nil {
+		t.Error("NewClient succeeded on a closed pool")
+	}
+}
+
+func TestSeparateTransportsDoNotShare(t *testing.T) {
+	url, conns := connCountingServer(t)
+
+	// WithConnectionPool gives each client a transport of its own
+	a := newTestClient(t, url, WithConnectionPool(10, 10, 0, time.Minute))
+	b := newTestClient(t, url, WithConnectionPool(10, 10, 0, time.Minute))
+	searchReused(t, a)
+	if searchReused(t, b) {
+		t.Error("clients with their own transports shared a connection")
+	}
+	if n := atomic.LoadInt32(conns); n != 2 {
+		t.Errorf("server accepted %d connections, want 2", n)
+	}
+}
+
+func TestClosedClient(t *testing.T) {
+	url, _ := connCountingServer(t)
+	client := newTestClient(t, url)
+	client.Close()
+	if err := client.Close(); err != nil {
+		t.Errorf("second Close = %v", err)
+	}
+	if _, err := client.Search(nil); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Search after Close error = %v, want ErrClientClosed", err)
+	}
+}
@@ -0,0 +1,64 @@
+package allnewsapi
+
+import "time"
+
+// Clock is the source of time for retries, backoff, hedging, rate limit
+// pacing and call durations. Tests can replace the real clock with a fake,
+// such as allnewsapitest.FakeClock, using WithClock. Network timeouts and
+// context deadlines always use the real clock.
+//
+// Every wait of the SDK can be cancelled or rearmed, so it uses NewTimer;
+// Clock has no After or Sleep.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a timer created by a Clock, like time.Timer.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// WithClock sets the clock used by the client. A nil clock is ignored.
+func WithClock(clock Clock) ClientOption {
+	return func(c *Client) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+// realClock is the Clock backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+type realTimer struct {
+	t *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.t.C
+}
+
+func (t realTimer) Stop() bool {
+	return t.t.Stop()
+}
+
+func (t realTimer) Reset(d time.Duration) bool {
+	return t.t.Reset(d)
+}
+
+// clockSetter is implemented by backoff policies that read the current
+// time, so that NewClient can give them the client clock.
+type clockSetter interface {
+	withClock(clock Clock) BackoffPolicy
+}
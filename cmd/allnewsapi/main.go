@@ -0,0 +1,239 @@
+// Command allnewsapi searches news articles and headlines from the command
+// line.
+//
+// Usage:
+//
+//	allnewsapi search --q bitcoin --lang en --max 10 --format table
+//	allnewsapi headlines --category technology --format json
+//
+// The API key is read from --api-key or the ALLNEWSAPI_API_KEY environment
+// variable.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	allnewsapi "github.com/AllNewsAPI/go-sdk"
+)
+
+const usage = `Usage: allnewsapi <search|headlines> [flags]
+
+Run "allnewsapi search --help" for the list of flags.
+`
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdout, os.Stderr, os.Getenv))
+}
+
+// config holds the parsed command line.
+type config struct {
+	command string
+	apiKey  string
+	baseURL string
+	format  string
+	all     bool
+	width   int
+	options *allnewsapi.SearchOptions
+}
+
+// run executes the command line args and returns the exit code.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer, getenv func(string) string) int {
+	cfg, err := parseArgs(args, stderr, getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "allnewsapi: %v\n", err)
+		return 2
+	}
+
+	var clientOpts []allnewsapi.ClientOption
+	if cfg.baseURL != "" {
+		clientOpts = append(clientOpts, allnewsapi.WithBaseURL(cfg.baseURL))
+	}
+	client, err := allnewsapi.NewClient(cfg.apiKey, clientOpts...)
+	if err != nil {
+		fmt.Fprintf(stderr, "allnewsapi: %v\n", err)
+		return 2
+	}
+
+	// With --all, a failure after the first page still prints the articles
+	// gathered so far before reporting the error
+	articles, total, fetchErr := fetch(ctx, client, cfg)
+	if fetchErr != nil && len(articles) == 0 {
+		printError(stderr, fetchErr)
+		return 1
+	}
+
+	if err := write(stdout, cfg, articles, total); err != nil {
+		fmt.Fprintf(stderr, "allnewsapi: %v\n", err)
+		return 1
+	}
+	if fetchErr != nil {
+		printError(stderr, fetchErr)
+		return 1
+	}
+	return 0
+}
+
+// parseArgs parses the subcommand and its flags.
+func parseArgs(args []string, stderr io.Writer, getenv func(string) string) (*config, error) {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return nil, errors.New("missing command")
+	}
+
+	cfg := &config{command: args[0], options: &allnewsapi.SearchOptions{}}
+	switch cfg.command {
+	case "search", "headlines":
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(stderr, usage)
+		return nil, flag.ErrHelp
+	default:
+		fmt.Fprint(stderr, usage)
+		return nil, fmt.Errorf("unknown command %q", cfg.command)
+	}
+
+	var lang, country, region, category, attributes, publisher string
+	var content bool
+
+	fs := flag.NewFlagSet("allnewsapi "+cfg.command, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&cfg.apiKey, "api-key", getenv("ALLNEWSAPI_API_KEY"), "API key (default $ALLNEWSAPI_API_KEY)")
+	fs.StringVar(&cfg.baseURL, "base-url", "", "API base URL")
+	fs.StringVar(&cfg.format, "format", "table", "output format: table, json, csv or ndjson")
+	fs.BoolVar(&cfg.all, "all", false, "fetch every page of results")
+	fs.IntVar(&cfg.width, "width", 120, "maximum table width")
+	fs.StringVar(&cfg.options.Query, "q", "", "search query")
+	fs.StringVar(&lang, "lang", "", "comma separated languages")
+	fs.StringVar(&country, "country", "", "comma separated countries")
+	fs.StringVar(&region, "region", "", "comma separated regions")
+	fs.StringVar(&category, "category", "", "comma separated categories")
+	fs.StringVar(&attributes, "attributes", "", "comma separated attributes to search in")
+	fs.StringVar(&publisher, "publisher", "", "comma separated publishers")
+	fs.IntVar(&cfg.options.Max, "max", 0, "maximum number of results per page (1-100)")
+	fs.IntVar(&cfg.options.Page, "page", 0, "page number")
+	fs.StringVar(&cfg.options.SortBy, "sortby", "", "sort by publishedAt or relevance")
+	fs.BoolVar(&content, "content", false, "include full content")
+	startDate := fs.String("start", "", "start date (YYYY-MM-DD or YYYY-MM-DD HH:MM:SS)")
+	endDate := fs.String("end", "", "end date (YYYY-MM-DD or YYYY-MM-DD HH:MM:SS)")
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	switch cfg.format {
+	case "table", "json", "csv", "ndjson":
+	default:
+		return nil, fmt.Errorf("unknown format %q", cfg.format)
+	}
+	if cfg.apiKey == "" {
+		return nil, errors.New("an API key is required, use --api-key or ALLNEWSAPI_API_KEY")
+	}
+
+	cfg.options.Lang = splitList(lang)
+	cfg.options.Country = splitList(country)
+	cfg.options.Region = splitList(region)
+	cfg.options.Category = splitList(category)
+	cfg.options.Attributes = splitList(attributes)
+	cfg.options.Publisher = splitList(publisher)
+	if *startDate != "" {
+		cfg.options.StartDate = *startDate
+	}
+	if *endDate != "" {
+		cfg.options.EndDate = *endDate
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "content" {
+			cfg.options.Content = allnewsapi.Bool(content)
+		}
+	})
+
+	return cfg, nil
+}
+
+// splitList splits a comma separated flag value.
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// fetch runs the query and returns the articles and the total reported by
+// the API. On error, the articles fetched so far are returned along with
+// the error.
+func fetch(ctx context.Context, client *allnewsapi.Client, cfg *config) ([]allnewsapi.Article, int64, error) {
+	pager := client.SearchPager(cfg.options)
+	if cfg.command == "headlines" {
+		pager = client.HeadlinesPager(cfg.options)
+	}
+
+	var articles []allnewsapi.Article
+	var total int64
+	for pager.HasNext() {
+		page, err := pager.Next(ctx)
+		if err != nil {
+			return articles, total, err
+		}
+		total = page.TotalArticles
+		articles = append(articles, page.Articles...)
+		if !cfg.all {
+			break
+		}
+	}
+	return articles, total, nil
+}
+
+// write prints articles in the configured format.
+func write(w io.Writer, cfg *config, articles []allnewsapi.Article, total int64) error {
+	switch cfg.format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(articles)
+	case "csv":
+		return allnewsapi.WriteCSV(w, articles)
+	case "ndjson":
+		return allnewsapi.WriteNDJSON(w, articles)
+	default:
+		if err := allnewsapi.RenderTable(w, articles, nil, cfg.width); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintf(w, "\n%d of %d articles\n", len(articles), total)
+		return err
+	}
+}
+
+// printError reports err, with the details of API errors.
+func printError(w io.Writer, err error) {
+	var apiErr *allnewsapi.APIError
+	if errors.As(err, &apiErr) {
+		message := apiErr.Message
+		if message == "" {
+			message = strings.TrimSpace(string(apiErr.Body))
+		}
+		fmt.Fprintf(w, "allnewsapi: API error (status %d): %s\n", apiErr.StatusCode, message)
+	} else {
+		fmt.Fprintf(w, "allnewsapi: %v\n", err)
+	}
+	if id := allnewsapi.RequestIDFromError(err); id != "" {
+		fmt.Fprintf(w, "request id: %s\n", id)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/http"
+	"strings"
+	"testing"
+
+	allnewsapi "github.com/AllNewsAPI/go-sdk"
+	"github.com/AllNewsAPI/go-sdk/allnewsapitest"
+)
+
+// env returns a getenv function serving the given variables.
+func env(vars map[string]string) func(string) string {
+	return func(name string) string { return vars[name] }
+}
+
+func TestParseArgs(t *testing.T) {
+	withKey := env(map[string]string{"ALLNEWSAPI_API_KEY": "env-key"})
+	cfg, err := parseArgs([]string{"headlines", "--lang", "en, fr,", "--category", "tech", "--max", "5",
+		"--page", "2", "--start", "2024-01-01", "--content=false", "--format", "csv", "--all"}, &bytes.Buffer{}, withKey)
+	if err != nil {
+		t.Fatalf("parseArgs: %v", err)
+	}
+	if cfg.command != "headlines" || cfg.apiKey != "env-key" || cfg.format != "csv" || !cfg.all {
+		t.Errorf("config = %+v, want headlines with the key from the environment, csv and --all", cfg)
+	}
+	o := cfg.options
+	if strings.Join(o.Lang, "|") != "en|fr" || strings.Join(o.Category, "|") != "tech" || o.Max != 5 || o.Page != 2 {
+		t.Errorf("options = %+v, want lang [en fr], category [tech], max 5, page 2", o)
+	}
+	if o.StartDate != "2024-01-01" || o.EndDate != nil {
+		t.Errorf("dates = %v, %v; want 2024-01-01 and unset", o.StartDate, o.EndDate)
+	}
+	if o.Content == nil || *o.Content {
+		t.Errorf("Content = %v, want explicitly false", o.Content)
+	}
+
+	// Flags override the environment, and Content stays unset by default
+	cfg, err = parseArgs([]string{"search", "--api-key", "flag-key"}, &bytes.Buffer{}, withKey)
+	if err != nil || cfg.apiKey != "flag-key" || cfg.options.Content != nil || cfg.format != "table" {
+		t.Errorf("parseArgs = %+v, %v; want the flag key and defaults", cfg, err)
+	}
+}
+
+func TestParseArgsErrors(t *testing.T) {
+	withKey := env(map[string]string{"ALLNEWSAPI_API_KEY": "k"})
+	tests := []struct {
+		name   string
+		args   []string
+		getenv func(string) string
+		want   string
+	}{
+		{"no command", nil, withKey, "missing command"},
+		{"unknown command", []string{"sources"}, withKey, `unknown command "sources"`},
+		{"unknown flag", []string{"search", "--nope"}, withKey, "flag provided but not defined"},
+		{"bad int", []string{"search", "--max", "many"}, withKey, "invalid value"},
+		{"unknown format", []string{"search", "--format", "xml"}, withKey, `unknown format "xml"`},
+		{"extra arguments", []string{"search", "bitcoin"}, withKey, "unexpected arguments: bitcoin"},
+		{"no key", []string{"search"}, env(nil), "an API key is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseArgs(tt.args, &bytes.Buffer{}, tt.getenv)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseArgs error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	var stderr bytes.Buffer
+	if _, err := parseArgs([]string{"help"}, &stderr, withKey); !errors.Is(err, flag.ErrHelp) || !strings.Contains(stderr.String(), "Usage:") {
+		t.Errorf("help: error %v, output %q; want flag.ErrHelp and the usage", err, stderr.String())
+	}
+}
+
+// runCLI runs the command line against a fixture server and returns the
+// exit code and outputs.
+func runCLI(t *testing.T, fixtures []allnewsapitest.Fixture, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+	server, shutdown := allnewsapitest.NewServer(fixtures...)
+	defer shutdown()
+
+	var out, errOut bytes.Buffer
+	args = append(args, "--api-key", "test-key", "--base-url", server.URL)
+	code = run(context.Background(), args, &out, &errOut, env(nil))
+	return code, out.String(), errOut.String()
+}
+
+func TestRunTable(t *testing.T) {
+	fixtures := []allnewsapitest.Fixture{{
+		Endpoint: "search",
+		Params:   map[string]string{"q": "bitcoin", "lang": "en,fr", "max": "2"},
+		Body: allnewsapitest.ResponseBody(&allnewsapi.SearchResponse{
+			TotalArticles: 40, Articles: allnewsapitest.GenerateArticles(1, 2),
+		}),
+	}}
+	code, stdout, stderr := runCLI(t, fixtures, "search", "--q", "bitcoin", "--lang", "en,fr", "--max", "2")
+	if code != 0 || stderr != "" {
+		t.Fatalf("exit code %d, stderr %q", code, stderr)
+	}
+	if !strings.Contains(stdout, "Article 1") || !strings.Contains(stdout, "Article 2") || !strings.HasSuffix(stdout, "\n2 of 40 articles\n") {
+		t.Errorf("stdout = %q, want a table of both articles and the total", stdout)
+	}
+}
+
+func TestRunJSONAll(t *testing.T) {
+	fixtures := []allnewsapitest.Fixture{allnewsapitest.PagedFixture("headlines", 2, 3)}
+	code, stdout, stderr := runCLI(t, fixtures, "headlines", "--format", "json", "--all")
+	if code != 0 || stderr != "" {
+		t.Fatalf("exit code %d, stderr %q", code, stderr)
+	}
+	var articles []allnewsapi.Article
+	if err := json.Unmarshal([]byte(stdout), &articles); err != nil {
+		t.Fatalf("stdout is not a JSON array: %v", err)
+	}
+	if len(articles) != 6 || articles[5].Title != "Article 6" {
+		t.Errorf("got %d articles, want the 6 articles of the 3 pages", len(articles))
+	}
+
+	// Without --all only the first page is fetched
+	_, stdout, _ = runCLI(t, fixtures, "headlines", "--format", "ndjson")
+	if n := strings.Count(stdout, "\n"); n != 2 {
+		t.Errorf("printed %d lines, want the 2 articles of the first page", n)
+	}
+}
+
+func TestRunAPIError(t *testing.T) {
+	fixtures := []allnewsapitest.Fixture{{Status: http.StatusUnauthorized, Body: []byte(`{"message":"invalid API key"}`)}}
+	code, stdout, stderr := runCLI(t, fixtures, "search", "--q", "x")
+	if code != 1 || stdout != "" {
+		t.Errorf("exit code %d, stdout %q; want 1 and no output", code, stdout)
+	}
+	if !strings.Contains(stderr, "API error (status 401): invalid API key") || !strings.Contains(stderr, "request id: ") {
+		t.Errorf("stderr = %q, want the API message and the request ID", stderr)
+	}
+}
+
+func TestRunAllKeepsPagesBeforeFailure(t *testing.T) {
+	fixtures := []allnewsapitest.Fixture{
+		{Endpoint: "search", Params: map[string]string{"page": "3"}, Status: http.StatusInternalServerError, Body: []byte(`{"message":"boom"}`)},
+		allnewsapitest.PagedFixture("search", 2, 4),
+	}
+	code, stdout, stderr := runCLI(t, fixtures, "search", "--format", "ndjson", "--all")
+	if code != 1 {
+		t.Errorf("exit code %d, want 1", code)
+	}
+	lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[3], `"Article 4"`) {
+		t.Errorf("stdout = %q, want the 4 articles of pages 1 and 2", stdout)
+	}
+	if !strings.Contains(stderr, "API error (status 500): boom") {
+		t.Errorf("stderr = %q, want the error of page 3", stderr)
+	}
+}
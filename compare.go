@@ -0,0 +1,114 @@
+package allnewsapi
+
+import (
+	"sort"
+	"strings"
+)
+
+// ArticleCmp compares two articles, returning a negative number when a sorts
+// before b, a positive number when a sorts after b and zero otherwise. It
+// has the signature expected by slices.SortFunc.
+type ArticleCmp = func(a, b Article) int
+
+// ByPublishedAt orders articles from oldest to newest. Articles without a
+// publication time sort last.
+func ByPublishedAt(a, b Article) int {
+	return compareTimes(a, b, 1)
+}
+
+// ByPublishedAtDesc orders articles from newest to oldest. Articles without a
+// publication time still sort last.
+func ByPublishedAtDesc(a, b Article) int {
+	return compareTimes(a, b, -1)
+}
+
+// ByTitle orders articles by title, byte-wise. Articles without a title
+// sort last.
+func ByTitle(a, b Article) int {
+	return compareStrings(a.Title, b.Title, 1)
+}
+
+// ByTitleDesc orders articles by title in reverse byte-wise order. Articles
+// without a title still sort last.
+func ByTitleDesc(a, b Article) int {
+	return compareStrings(a.Title, b.Title, -1)
+}
+
+// BySourceName orders articles by source name, byte-wise. Articles without a
+// source name sort last.
+func BySourceName(a, b Article) int {
+	return compareStrings(a.Source.Name, b.Source.Name, 1)
+}
+
+// BySourceNameDesc orders articles by source name in reverse byte-wise order.
+// Articles without a source name still sort last.
+func BySourceNameDesc(a, b Article) int {
+	return compareStrings(a.Source.Name, b.Source.Name, -1)
+}
+
+// compareTimes compares the publication times of a and b, multiplied by
+// sign, sorting zero times last whatever the sign.
+func compareTimes(a, b Article, sign int) int {
+	switch az, bz := a.PublishedAt.IsZero(), b.PublishedAt.IsZero(); {
+	case az && bz:
+		return 0
+	case az:
+		return 1
+	case bz:
+		return -1
+	case a.PublishedAt.Before(b.PublishedAt):
+		return -sign
+	case a.PublishedAt.After(b.PublishedAt):
+		return sign
+	default:
+		return 0
+	}
+}
+
+// compareStrings compares a and b, multiplied by sign, sorting empty strings
+// last whatever the sign.
+func compareStrings(a, b string, sign int) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	default:
+		return sign * strings.Compare(a, b)
+	}
+}
+
+// Reversed returns a comparator ordering articles in the opposite order of
+// cmp. Articles that cmp sorts last, such as those without a publication
+// time for ByPublishedAt, therefore sort first; use the Desc comparators,
+// such as ByPublishedAtDesc, to keep them last.
+func Reversed(cmp ArticleCmp) ArticleCmp {
+	return func(a, b Article) int {
+		return cmp(b, a)
+	}
+}
+
+// Chain returns a comparator ordering articles by the first of cmps that
+// tells them apart. For example, Chain(ByPublishedAtDesc, ByTitle) orders
+// articles from newest to oldest and by title within the same time.
+func Chain(cmps ...ArticleCmp) ArticleCmp {
+	return func(a, b Article) int {
+		for _, cmp := range cmps {
+			if c := cmp(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+}
+
+// SortArticles sorts articles in place by cmps, as combined by Chain. The
+// sort is stable.
+func SortArticles(articles []Article, cmps ...ArticleCmp) {
+	cmp := Chain(cmps...)
+	sort.SliceStable(articles, func(i, j int) bool {
+		return cmp(articles[i], articles[j]) < 0
+	})
+}
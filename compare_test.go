@@ -0,0 +1,143 @@
+package allnewsapi
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// comparators lists the comparators under test, with whether each sorts
+// articles that miss its value last.
+var comparators = []struct {
+	name        string
+	cmp         ArticleCmp
+	missing     func(Article) bool
+	missingLast bool
+}{
+	{"ByPublishedAt", ByPublishedAt, func(a Article) bool { return a.PublishedAt.IsZero() }, true},
+	{"ByPublishedAtDesc", ByPublishedAtDesc, func(a Article) bool { return a.PublishedAt.IsZero() }, true},
+	{"ByTitle", ByTitle, func(a Article) bool { return a.Title == "" }, true},
+	{"ByTitleDesc", ByTitleDesc, func(a Article) bool { return a.Title == "" }, true},
+	{"BySourceName", BySourceName, func(a Article) bool { return a.Source.Name == "" }, true},
+	{"BySourceNameDesc", BySourceNameDesc, func(a Article) bool { return a.Source.Name == "" }, true},
+	{"Reversed(ByPublishedAt)", Reversed(ByPublishedAt), func(a Article) bool { return a.PublishedAt.IsZero() }, false},
+}
+
+// randomArticles returns n articles drawing their titles, source names and
+// times from small sets, so that ties and missing values are frequent. The
+// Description of each article is its index.
+func randomArticles(rng *rand.Rand, n int) []Article {
+	words := []string{"", "alpha", "beta", "Beta", "gamma", "élan"}
+	articles := make([]Article, n)
+	for i := range articles {
+		a := &articles[i]
+		a.Description = strconv.Itoa(i)
+		a.Title = words[rng.Intn(len(words))]
+		a.Source.Name = words[rng.Intn(len(words))]
+		if minutes := rng.Intn(5); minutes > 0 {
+			a.PublishedAt = testTime.Add(time.Duration(minutes) * time.Minute)
+		}
+	}
+	return articles
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+func TestComparatorsAntisymmetric(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	articles := randomArticles(rng, 40)
+	for _, c := range comparators {
+		t.Run(c.name, func(t *testing.T) {
+			for _, a := range articles {
+				for _, b := range articles {
+					if ab, ba := sign(c.cmp(a, b)), sign(c.cmp(b, a)); ab != -ba {
+						t.Fatalf("cmp(%+v, %+v) = %d but cmp(b, a) = %d", a, b, ab, ba)
+					}
+				}
+				if c.cmp(a, a) != 0 {
+					t.Fatalf("cmp(%+v, a) != 0", a)
+				}
+			}
+		})
+	}
+}
+
+func TestSortArticlesProperties(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, c := range comparators {
+		t.Run(c.name, func(t *testing.T) {
+			for round := 0; round < 50; round++ {
+				in := randomArticles(rng, rng.Intn(30))
+				out := append([]Article(nil), in...)
+				SortArticles(out, c.cmp)
+
+				// The output is a permutation of the input
+				seen := make(map[string]bool, len(out))
+				for _, a := range out {
+					seen[a.Description] = true
+				}
+				if len(out) != len(in) || len(seen) != len(in) {
+					t.Fatalf("sorted %d articles into %d distinct of %d", len(in), len(seen), len(out))
+				}
+
+				for i := 1; i < len(out); i++ {
+					prev, cur := out[i-1], out[i]
+					if c.cmp(prev, cur) > 0 {
+						t.Fatalf("articles %d and %d out of order: %+v, %+v", i-1, i, prev, cur)
+					}
+					// Equal articles keep their input order
+					if c.cmp(prev, cur) == 0 && articleIndex(prev) > articleIndex(cur) {
+						t.Fatalf("sort is not stable at %d: %+v, %+v", i, prev, cur)
+					}
+					if c.missingLast && c.missing(prev) && !c.missing(cur) {
+						t.Fatalf("article %d without a value sorts before article %d with one", i-1, i)
+					}
+				}
+			}
+		})
+	}
+}
+
+// articleIndex returns the index randomArticles gave a.
+func articleIndex(a Article) int {
+	i, _ := strconv.Atoi(a.Description)
+	return i
+}
+
+func TestDescComparators(t *testing.T) {
+	articles := []Article{
+		{Title: "b", PublishedAt: testTime},
+		{},
+		{Title: "c", PublishedAt: testTime.Add(time.Hour)},
+		{Title: "a", PublishedAt: testTime.Add(-time.Hour)},
+	}
+	tests := []struct {
+		name string
+		cmps []ArticleCmp
+		want []string
+	}{
+		{"ByPublishedAtDesc", []ArticleCmp{ByPublishedAtDesc}, []string{"c", "b", "a", ""}},
+		{"ByTitleDesc", []ArticleCmp{ByTitleDesc}, []string{"c", "b", "a", ""}},
+		// Reversed moves the article without values first
+		{"Reversed", []ArticleCmp{Reversed(ByPublishedAt)}, []string{"", "c", "b", "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := append([]Article(nil), articles...)
+			SortArticles(got, tt.cmps...)
+			if !reflect.DeepEqual(titles(got), tt.want) {
+				t.Errorf("sorted titles = %q, want %q", titles(got), tt.want)
+			}
+		})
+	}
+}
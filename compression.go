@@ -0,0 +1,78 @@
+package allnewsapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// compressionMode selects the Accept-Encoding behavior of the client.
+type compressionMode int
+
+const (
+	compressionDefault compressionMode = iota // leave it to the transport
+	compressionOn                             // request gzip and decompress explicitly
+	compressionOff                            // request identity encoding
+)
+
+// WithCompression controls response compression. When enabled, the client
+// explicitly requests gzip and decompresses responses itself, even when the
+// transport has DisableCompression set, and reports compressed and
+// uncompressed sizes in RequestMetrics. When disabled, it requests identity
+// encoding, which is useful when inspecting traffic. Without this option the
+// transport's default behavior applies.
+func WithCompression(enabled bool) ClientOption {
+	return func(c *Client) {
+		if enabled {
+			c.compression = compressionOn
+		} else {
+			c.compression = compressionOff
+		}
+	}
+}
+
+// setAcceptEncoding sets the Accept-Encoding header of req according to the
+// compression mode.
+func (c *Client) setAcceptEncoding(req *http.Request) {
+	switch c.compression {
+	case compressionOn:
+		req.Header.Set("Accept-Encoding", "gzip")
+	case compressionOff:
+		req.Header.Set("Accept-Encoding", "identity")
+	}
+}
+
+// readBody reads the body of resp into a pooled buffer, decompressing it when
+// compression was requested explicitly and the server used gzip. It also
+// returns the compressed size, 0 when the body was not decompressed here,
+// and a function returning the buffer to the pool once the body is no longer
+// used.
+func (c *Client) readBody(resp *http.Response) (body []byte, compressedSize int64, release func(), err error) {
+	if c.compression != compressionOn || !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		buf, err := readPooled(resp.Body, resp.ContentLength)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		return buf.Bytes(), 0, func() { putBuffer(buf) }, nil
+	}
+
+	compressed, err := readPooled(resp.Body, resp.ContentLength)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	defer putBuffer(compressed)
+
+	zr, err := gzip.NewReader(bytes.NewReader(compressed.Bytes()))
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("invalid gzip body: %w", err)
+	}
+	defer zr.Close()
+
+	buf, err := readPooled(zr, 0)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("invalid gzip body: %w", err)
+	}
+	return buf.Bytes(), int64(compressed.Len()), func() { putBuffer(buf) }, nil
+}
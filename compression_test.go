@@ -0,0 +1,103 @@
+package allnewsapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// gzipServer starts a server answering every request with body compressed
+// by gzip, whatever the request asked for, and recording the requests.
+func gzipServer(t *testing.T, body []byte) (url string, compressed []byte, log *requestLog) {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(body)
+	zw.Close()
+
+	log = &requestLog{}
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		log.add(r)
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(buf.Bytes())
+	})
+	return server.URL, buf.Bytes(), log
+}
+
+func TestCompressionDecodesGzip(t *testing.T) {
+	body := fatResponseBody(20, 2000)
+	url, compressed, log := gzipServer(t, body)
+
+	var metrics RequestMetrics
+	client := newTestClient(t, url, WithCompression(true),
+		WithMetrics(func(m RequestMetrics) { metrics = m }))
+	resp, err := client.Search(nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	if accept := log.all()[0].Header.Get("Accept-Encoding"); accept != "gzip" {
+		t.Errorf("Accept-Encoding = %q, want gzip", accept)
+	}
+	if len(resp.Articles) != 20 || resp.Articles[19].Title != "Article 19" || len(resp.Articles[0].Content) != 2000 {
+		t.Fatalf("decoded %d articles, want the 20 articles of the fixture", len(resp.Articles))
+	}
+	if metrics.CompressedBytes != int64(len(compressed)) {
+		t.Errorf("CompressedBytes = %d, want %d", metrics.CompressedBytes, len(compressed))
+	}
+	if metrics.UncompressedBytes != int64(len(body)) {
+		t.Errorf("UncompressedBytes = %d, want %d", metrics.UncompressedBytes, len(body))
+	}
+	if metrics.CompressedBytes >= metrics.UncompressedBytes {
+		t.Errorf("CompressedBytes = %d, want less than %d", metrics.CompressedBytes, metrics.UncompressedBytes)
+	}
+}
+
+func TestCompressionInvalidGzip(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write([]byte(`{"totalArticles":0,"articles":[]}`))
+	})
+	_, err := newTestClient(t, server.URL, WithCompression(true)).Search(nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid gzip body") {
+		t.Errorf("Search error = %v, want an invalid gzip body error", err)
+	}
+}
+
+func TestCompressionModes(t *testing.T) {
+	body := fatResponseBody(3, 100)
+	tests := []struct {
+		name       string
+		opts       []ClientOption
+		wantAccept string
+	}{
+		// The transport adds and strips gzip by itself and reports no
+		// compressed size
+		{"default", nil, ""},
+		{"disabled", []ClientOption{WithCompression(false)}, "identity"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &requestLog{}
+			server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				log.add(r)
+				w.Write(body)
+			})
+			var metrics RequestMetrics
+			opts := append(tt.opts, WithMetrics(func(m RequestMetrics) { metrics = m }))
+			if _, err := newTestClient(t, server.URL, opts...).Search(nil); err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+
+			accept := log.all()[0].Header.Get("Accept-Encoding")
+			if tt.wantAccept != "" && accept != tt.wantAccept {
+				t.Errorf("Accept-Encoding = %q, want %q", accept, tt.wantAccept)
+			}
+			if metrics.CompressedBytes != 0 || metrics.UncompressedBytes != int64(len(body)) {
+				t.Errorf("sizes = %d/%d, want 0/%d", metrics.CompressedBytes, metrics.UncompressedBytes, len(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package allnewsapi
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrContentUnavailable is returned when the full content of an article
+// cannot be obtained.
+var ErrContentUnavailable = errors.New("content unavailable")
+
+// ContentState tells whether an article carries its full content.
+type ContentState int
+
+const (
+	ContentFull        ContentState = iota // Content looks complete
+	ContentTruncated                       // Content was cut by the API
+	ContentUnavailable                     // Content is empty or a placeholder
+)
+
+func (s ContentState) String() string {
+	switch s {
+	case ContentFull:
+		return "full"
+	case ContentTruncated:
+		return "truncated"
+	case ContentUnavailable:
+		return "unavailable"
+	default:
+		return "ContentState(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// ContentStatus describes the content of an article.
+type ContentStatus struct {
+	State ContentState
+
+	// Remaining is the number of characters the API reported as cut, or -1
+	// when unknown. It is only set for ContentTruncated.
+	Remaining int
+}
+
+// contentMarker is a pattern identifying truncated or unavailable content.
+// For truncation markers, the first submatch, when present, is the number
+// of characters cut.
+type contentMarker struct {
+	pattern *regexp.Regexp
+	state   ContentState
+}
+
+// contentMarkers are checked in order against the trimmed content. Add new
+// markers here.
+var contentMarkers = []contentMarker{
+	// "… [+1234 chars]", "... [1234 characters]"
+	{regexp.MustCompile(`(?i)(?:…|\.\.\.)?\s*\[\+?(\d+)\s*(?:chars?|characters?)\]$`), ContentTruncated},
+	// "[1234 chars remaining]"
+	{regexp.MustCompile(`(?i)\[(\d+)\s*(?:chars?|characters?)\s+remaining\]$`), ContentTruncated},
+	// "[...]", "[…]"
+	{regexp.MustCompile(`\[(?:…|\.\.\.)\]$`), ContentTruncated},
+	// Placeholders sent instead of content
+	{regexp.MustCompile(`(?i)^(?:\[removed\]|only available in paid plans|content not available)$`), ContentUnavailable},
+}
+
+// ContentStatus reports whether the article content is complete, truncated
+// by the API (for example ending in "… [+1234 chars]") or unavailable.
+func (a Article) ContentStatus() ContentStatus {
+	content := strings.TrimSpace(a.Content)
+	if content == "" {
+		return ContentStatus{State: ContentUnavailable, Remaining: -1}
+	}
+
+	for _, m := range contentMarkers {
+		match := m.pattern.FindStringSubmatch(content)
+		if match == nil {
+			continue
+		}
+		status := ContentStatus{State: m.state, Remaining: -1}
+		if m.state == ContentTruncated && len(match) > 1 {
+			if n, err := strconv.Atoi(match[1]); err == nil {
+				status.Remaining = n
+			}
+		}
+		return status
+	}
+	return ContentStatus{State: ContentFull, Remaining: -1}
+}
@@ -0,0 +1,85 @@
+package allnewsapi
+
+import (
+	"strings"
+	"testing"
+)
+
+// contentCases are contents with their expected status.
+var contentCases = []struct {
+	content   string
+	want      ContentState
+	remaining int
+}{
+	{"", ContentUnavailable, -1},
+	{"  \n\t", ContentUnavailable, -1},
+	{"A complete article.", ContentFull, -1},
+
+	// "… [+1234 chars]" and its variants
+	{"The start of it… [+1234 chars]", ContentTruncated, 1234},
+	{"The start of it... [+1234 chars]", ContentTruncated, 1234},
+	{"The start of it [+1 char]", ContentTruncated, 1},
+	{"The start of it... [1234 characters]", ContentTruncated, 1234},
+	{"The start of it… [+42 CHARS]  \n", ContentTruncated, 42},
+	{"The start of it…[+7chars]", ContentTruncated, 7},
+
+	// "[1234 chars remaining]"
+	{"The start of it [1234 chars remaining]", ContentTruncated, 1234},
+	{"The start of it [5 Characters Remaining]", ContentTruncated, 5},
+
+	// "[...]" without a count
+	{"The start of it [...]", ContentTruncated, -1},
+	{"The start of it […]", ContentTruncated, -1},
+
+	// Placeholders
+	{"[Removed]", ContentUnavailable, -1},
+	{"  ONLY AVAILABLE IN PAID PLANS ", ContentUnavailable, -1},
+	{"Content not available", ContentUnavailable, -1},
+
+	// Markers only count at the end, and placeholders only alone
+	{"The [+1234 chars] marker in the middle.", ContentFull, -1},
+	{"It ends with an ellipsis...", ContentFull, -1},
+	{"See [...] below.", ContentFull, -1},
+	{"This content not available elsewhere.", ContentFull, -1},
+	{"The start of it [+many chars]", ContentFull, -1},
+	{"The start of it [1234 words]", ContentFull, -1},
+	{"The start of it [+99999999999999999999 chars]", ContentTruncated, -1},
+}
+
+func TestContentStatus(t *testing.T) {
+	for _, tt := range contentCases {
+		t.Run(tt.content, func(t *testing.T) {
+			got := Article{Content: tt.content}.ContentStatus()
+			if got.State != tt.want || got.Remaining != tt.remaining {
+				t.Errorf("ContentStatus() = %v/%d, want %v/%d", got.State, got.Remaining, tt.want, tt.remaining)
+			}
+		})
+	}
+}
+
+// TestContentMarkersCovered checks that every marker of the table matches
+// one of contentCases, so new markers come with a test case.
+func TestContentMarkersCovered(t *testing.T) {
+	for i, m := range contentMarkers {
+		covered := false
+		for _, tt := range contentCases {
+			covered = covered || m.pattern.MatchString(strings.TrimSpace(tt.content))
+		}
+		if !covered {
+			t.Errorf("marker %d (%s) has no test case", i, m.pattern)
+		}
+	}
+}
+
+func TestContentStateString(t *testing.T) {
+	for state, want := range map[ContentState]string{
+		ContentFull:        "full",
+		ContentTruncated:   "truncated",
+		ContentUnavailable: "unavailable",
+		ContentState(9):    "ContentState(9)",
+	} {
+		if got := state.String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package allnewsapi
+
+import "context"
+
+// CountResult is the result of CountDetailed.
+type CountResult struct {
+	TotalArticles int64
+	RateLimit     *RateLimit // nil when the API sent no rate limit headers
+	RequestID     string
+	Meta          *Meta
+}
+
+// Count returns the number of articles matching options without downloading
+// them. It issues the smallest possible search request: max=1 and
+// content=false, on the first page.
+func (c *Client) Count(ctx context.Context, options *SearchOptions, callOpts ...CallOption) (int64, error) {
+	result, err := c.CountDetailed(ctx, options, callOpts...)
+	if err != nil {
+		return 0, err
+	}
+	return result.TotalArticles, nil
+}
+
+// CountDetailed is like Count but also returns the response metadata.
+func (c *Client) CountDetailed(ctx context.Context, options *SearchOptions, callOpts ...CallOption) (*CountResult, error) {
+	params, err := c.params(countOptions(options))
+	if err != nil {
+		return nil, err
+	}
+	// The page is dropped after the defaults are merged, so that a default
+	// Page does not move the count off the first page
+	params.Del("page")
+
+	resp, err := c.queryParams(ctx, searchEndpoint, params, callOpts)
+	if err != nil {
+		return nil, err
+	}
+	return &CountResult{
+		TotalArticles: resp.TotalArticles,
+		RateLimit:     resp.RateLimit,
+		RequestID:     resp.RequestID,
+		Meta:          resp.Meta,
+	}, nil
+}
+
+// countOptions returns a copy of options requesting a single article without
+// content.
+func countOptions(options *SearchOptions) *SearchOptions {
+	count := options.Clone()
+	if count == nil {
+		count = &SearchOptions{}
+	}
+	count.Content = Bool(false)
+	count.Page = 0
+	count.pageSet = false
+	return count.SetMax(1)
+}
@@ -0,0 +1,59 @@
+package allnewsapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestCountQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults *SearchOptions
+		options  *SearchOptions
+		want     string
+	}{
+		{"nil options", nil, nil, "apikey=test-key&content=false&max=1"},
+		{"query", nil, &SearchOptions{Query: "bitcoin", Lang: []string{"en"}},
+			"apikey=test-key&content=false&lang=en&max=1&q=bitcoin"},
+		{"overridden fields", nil, (&SearchOptions{Query: "q", Content: Bool(true), Max: 100}).SetPage(9),
+			"apikey=test-key&content=false&max=1&q=q"},
+		{"client defaults", &SearchOptions{Content: Bool(true), Max: 50, Page: 4, Country: []string{"us"}},
+			&SearchOptions{Query: "q"}, "apikey=test-key&content=false&country=us&max=1&q=q"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, log := recordingServer(t, `{"totalArticles":1234,"articles":[{"title":"x"}]}`)
+			var opts []ClientOption
+			if tt.defaults != nil {
+				opts = append(opts, WithDefaultSearchOptions(*tt.defaults))
+			}
+			client := newTestClient(t, server.URL, opts...)
+
+			n, err := client.Count(context.Background(), tt.options)
+			if err != nil {
+				t.Fatalf("Count: %v", err)
+			}
+			if n != 1234 {
+				t.Errorf("Count = %d, want 1234", n)
+			}
+			if got := log.all()[0].URL.Query().Encode(); got != tt.want {
+				t.Errorf("query = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountDetailed(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "9")
+		w.Write([]byte(`{"totalArticles":7,"articles":[]}`))
+	})
+	result, err := newTestClient(t, server.URL).CountDetailed(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("CountDetailed: %v", err)
+	}
+	if result.TotalArticles != 7 || result.RateLimit == nil || result.RateLimit.Remaining != 9 || result.RequestID == "" || result.Meta == nil {
+		t.Errorf("result = %+v, want the total, rate limit, request ID and metadata", result)
+	}
+}
@@ -0,0 +1,198 @@
+package allnewsapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cursorVersion is the version of the Cursor JSON format.
+const cursorVersion = 1
+
+// ErrCursorMismatch is returned when resuming a Cursor on an endpoint other
+// than the one it was created for, or with client defaults that change its
+// options.
+var ErrCursorMismatch = errors.New("cursor does not match the options")
+
+// Cursor records the position of a Pager so that an interrupted crawl can be
+// resumed, possibly by another process, without fetching a page twice or
+// skipping one. Cursors are obtained from Pager.Cursor and stored with
+// encoding/json.
+type Cursor struct {
+	Endpoint string    // "search" or "headlines"
+	Options  string    // Canonical encoding of the options and client defaults, without the page
+	NextPage int       // Page to fetch next, 0 for the first page
+	Newest   time.Time // Newest PublishedAt seen so far, zero if none
+}
+
+type cursorJSON struct {
+	Version  int        `json:"v"`
+	Endpoint string     `json:"endpoint"`
+	Options  string     `json:"options"`
+	NextPage int        `json:"nextPage,omitempty"`
+	Newest   *time.Time `json:"newest,omitempty"`
+}
+
+// MarshalJSON encodes the cursor in a versioned format.
+func (c Cursor) MarshalJSON() ([]byte, error) {
+	aux := cursorJSON{
+		Version:  cursorVersion,
+		Endpoint: c.Endpoint,
+		Options:  c.Options,
+		NextPage: c.NextPage,
+	}
+	if !c.Newest.IsZero() {
+		aux.Newest = &c.Newest
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON decodes a cursor produced by MarshalJSON.
+func (c *Cursor) UnmarshalJSON(data []byte) error {
+	var aux cursorJSON
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.Version != cursorVersion {
+		return fmt.Errorf("unsupported cursor version %d", aux.Version)
+	}
+
+	*c = Cursor{Endpoint: aux.Endpoint, Options: aux.Options, NextPage: aux.NextPage}
+	if aux.Newest != nil {
+		c.Newest = *aux.Newest
+	}
+	return nil
+}
+
+// canonicalOptions encodes options merged over the client defaults, without
+// their page, so that two option sets selecting the same results encode
+// identically and a cursor does not match a client with other defaults.
+func canonicalOptions(defaults, options *SearchOptions) (string, error) {
+	unpaged := defaults.Merge(options)
+	if unpaged == nil {
+		unpaged = &SearchOptions{}
+	}
+	unpaged.Page = 0
+	unpaged.pageSet = false
+
+	params, err := buildParams(unpaged)
+	if err != nil {
+		return "", err
+	}
+	return params.Encode(), nil
+}
+
+// parseCanonicalOptions decodes options encoded by canonicalOptions.
+func parseCanonicalOptions(canonical string) (*SearchOptions, error) {
+	params, err := url.ParseQuery(canonical)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor options: %w", err)
+	}
+
+	options := &SearchOptions{}
+	for name, values := range params {
+		value := values[0]
+		list := strings.Split(value, ",")
+		switch name {
+		case "q":
+			options.Query = value
+		case "startDate":
+			options.StartDate = value
+		case "endDate":
+			options.EndDate = value
+		case "content":
+			options.Content = Bool(value == "true")
+		case "lang":
+			options.Lang = list
+		case "country":
+			options.Country = list
+		case "region":
+			options.Region = list
+		case "category":
+			options.Category = list
+		case "attributes":
+			options.Attributes = list
+		case "publisher":
+			options.Publisher = list
+		case "max":
+			max, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cursor options: max %q", value)
+			}
+			options.SetMax(max)
+		case "sortby":
+			options.SortBy = value
+		case "format":
+			options.Format = value
+		default:
+			return nil, fmt.Errorf("invalid cursor options: unknown parameter %q", name)
+		}
+	}
+	return options, nil
+}
+
+// Cursor returns the position of the pager. It returns ErrNoMorePages once
+// the last page was fetched, and other errors when the options cannot be
+// encoded.
+func (p *Pager) Cursor() (Cursor, error) {
+	if p.next == nil {
+		return Cursor{}, ErrNoMorePages
+	}
+	canonical, err := canonicalOptions(p.defaults, p.next)
+	if err != nil {
+		return Cursor{}, err
+	}
+	return Cursor{
+		Endpoint: p.endpoint,
+		Options:  canonical,
+		NextPage: p.next.Page,
+		Newest:   p.newest,
+	}, nil
+}
+
+// ResumeSearch returns a search Pager continuing from cursor with the
+// options recorded in it. The options include the client defaults in effect
+// when the cursor was created; if the defaults of c would change them,
+// ErrCursorMismatch is returned instead of mixing result sets. ctx is only
+// checked before the pager is built; pass a context to each call to Next.
+func (c *Client) ResumeSearch(ctx context.Context, cursor Cursor) (*Pager, error) {
+	return resume(ctx, c.SearchPager, searchEndpoint, cursor, c.defaults)
+}
+
+// ResumeHeadlines returns a headlines Pager continuing from cursor, like
+// ResumeSearch.
+func (c *Client) ResumeHeadlines(ctx context.Context, cursor Cursor) (*Pager, error) {
+	return resume(ctx, c.HeadlinesPager, headlinesEndpoint, cursor, c.defaults)
+}
+
+func resume(ctx context.Context, newPager func(*SearchOptions) *Pager, ep endpoint, cursor Cursor, defaults *SearchOptions) (*Pager, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if cursor.Endpoint != ep.name {
+		return nil, fmt.Errorf("%w: cursor is for %q, not %q", ErrCursorMismatch, cursor.Endpoint, ep.name)
+	}
+	next, err := parseCanonicalOptions(cursor.Options)
+	if err != nil {
+		return nil, err
+	}
+	canonical, err := canonicalOptions(defaults, next)
+	if err != nil {
+		return nil, err
+	}
+	if canonical != cursor.Options {
+		return nil, fmt.Errorf("%w: cursor has %q, client defaults make it %q", ErrCursorMismatch, cursor.Options, canonical)
+	}
+	if cursor.NextPage > 0 {
+		next.SetPage(cursor.NextPage)
+	}
+
+	pager := newPager(next)
+	pager.newest = cursor.Newest
+	return pager, nil
+}
@@ -0,0 +1,229 @@
+package allnewsapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+// pagedServer serves pages pages of two articles from testArticles, page N
+// reporting nextPage N+1 until the last one, and records the requests.
+func pagedServer(t *testing.T, pages int) (string, *requestLog) {
+	articles := testArticles(2 * pages)
+	log := &requestLog{}
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		log.add(r)
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil {
+			page = 1
+		}
+		resp := &SearchResponse{TotalArticles: int64(len(articles)), CurrentPage: page, TotalPages: pages}
+		if page <= pages {
+			resp.Articles = articles[2*(page-1) : 2*page]
+		}
+		if page < pages {
+			next := page + 1
+			resp.NextPage = &next
+		}
+		w.Write([]byte(responseBody(t, resp)))
+	})
+	return server.URL, log
+}
+
+// pages returns the page parameter of every request in log, "" when absent.
+func pages(log *requestLog) []string {
+	var out []string
+	for _, r := range log.all() {
+		out = append(out, r.URL.Query().Get("page"))
+	}
+	return out
+}
+
+func TestCursorResume(t *testing.T) {
+	url, log := pagedServer(t, 5)
+	options := &SearchOptions{Query: "q", Lang: []string{"en"}}
+	ctx := context.Background()
+
+	// Fetch two pages, then save the position
+	pager := newTestClient(t, url).SearchPager(options)
+	var articles []Article
+	for i := 0; i < 2; i++ {
+		page, err := pager.Next(ctx)
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		articles = append(articles, page.Articles...)
+	}
+	cursor, err := pager.Cursor()
+	if err != nil {
+		t.Fatalf("Cursor after page 2 of 5: %v", err)
+	}
+	saved, err := json.Marshal(cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Resume from the saved cursor with a new client, as another process
+	// would
+	var restored Cursor
+	if err := json.Unmarshal(saved, &restored); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if restored != cursor {
+		t.Errorf("cursor round trip: got %+v, want %+v", restored, cursor)
+	}
+	resumed, err := newTestClient(t, url).ResumeSearch(ctx, restored)
+	if err != nil {
+		t.Fatalf("ResumeSearch: %v", err)
+	}
+	if !resumed.Newest().Equal(testTime) {
+		t.Errorf("Newest = %s, want %s carried over from the cursor", resumed.Newest(), testTime)
+	}
+	rest, err := CollectAll(ctx, resumed)
+	if err != nil {
+		t.Fatalf("CollectAll: %v", err)
+	}
+	articles = append(articles, rest...)
+
+	// Every page was fetched exactly once, in order, with the options of
+	// the cursor
+	if got, want := fmt.Sprint(pages(log)), "[ 2 3 4 5]"; got != want {
+		t.Errorf("pages fetched = %s, want %s", got, want)
+	}
+	for _, r := range log.all() {
+		if q := r.URL.Query(); q.Get("q") != "q" || q.Get("lang") != "en" {
+			t.Errorf("request %s, want the options of the cursor", r.URL.RawQuery)
+		}
+	}
+	want := testArticles(10)
+	if len(articles) != len(want) {
+		t.Fatalf("got %d articles, want %d", len(articles), len(want))
+	}
+	for i := range articles {
+		if articles[i].URL != want[i].URL {
+			t.Errorf("article %d = %s, want %s", i, articles[i].URL, want[i].URL)
+		}
+	}
+
+	if _, err := resumed.Cursor(); !errors.Is(err, ErrNoMorePages) {
+		t.Errorf("Cursor after the last page error = %v, want ErrNoMorePages", err)
+	}
+}
+
+func TestCursorMismatch(t *testing.T) {
+	url, log := pagedServer(t, 3)
+	defaults := WithDefaultSearchOptions(SearchOptions{Country: []string{"us"}})
+	client := newTestClient(t, url, defaults)
+	pager := client.SearchPager(&SearchOptions{Query: "q"})
+	if _, err := pager.Next(context.Background()); err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	cursor, err := pager.Cursor()
+	if err != nil {
+		t.Fatalf("Cursor: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		client  *Client
+		resume  func(*Client, context.Context, Cursor) (*Pager, error)
+		wantErr bool
+	}{
+		{"same client", client, (*Client).ResumeSearch, false},
+		{"same client defaults", newTestClient(t, url, defaults), (*Client).ResumeSearch, false},
+		{"no client defaults", newTestClient(t, url), (*Client).ResumeSearch, false},
+		{"defaults for a recorded field", newTestClient(t, url, WithDefaultSearchOptions(SearchOptions{Country: []string{"fr"}})),
+			(*Client).ResumeSearch, false},
+		{"defaults adding a field", newTestClient(t, url, WithDefaultSearchOptions(SearchOptions{Max: 20})),
+			(*Client).ResumeSearch, true},
+		{"other endpoint", client, (*Client).ResumeHeadlines, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resumed, err := tt.resume(tt.client, context.Background(), cursor)
+			if tt.wantErr {
+				if !errors.Is(err, ErrCursorMismatch) {
+					t.Errorf("resume error = %v, want ErrCursorMismatch", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resume: %v", err)
+			}
+			before := len(log.all())
+			if _, err := resumed.Next(context.Background()); err != nil {
+				t.Fatalf("Next: %v", err)
+			}
+			q := log.all()[before].URL.Query()
+			if q.Get("page") != "2" || q.Get("q") != "q" || q.Get("country") != "us" {
+				t.Errorf("resumed with %s, want page 2 of the cursor options", log.all()[before].URL.RawQuery)
+			}
+		})
+	}
+}
+
+func TestCursorErrors(t *testing.T) {
+	client := newTestClient(t, "http://127.0.0.1:0")
+	ctx := context.Background()
+
+	// Options that cannot be encoded are not reported as the end
+	if _, err := client.SearchPager(&SearchOptions{StartDate: 5}).Cursor(); err == nil || errors.Is(err, ErrNoMorePages) {
+		t.Errorf("Cursor with invalid options error = %v, want an encoding error", err)
+	}
+
+	if _, err := client.ResumeSearch(ctx, Cursor{Endpoint: "search", Options: "q=a&sentiment=x"}); err == nil {
+		t.Error("resumed a cursor with an unknown parameter")
+	}
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := client.ResumeSearch(cancelled, Cursor{Endpoint: "search"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("ResumeSearch with a cancelled context error = %v, want context.Canceled", err)
+	}
+}
+
+func TestCursorOptionsRoundTrip(t *testing.T) {
+	for _, options := range []*SearchOptions{
+		nil,
+		{Query: `"climate change" & más`},
+		{
+			Query:      "q",
+			StartDate:  testTime,
+			EndDate:    "2024-03-11",
+			Content:    Bool(false),
+			Lang:       []string{"en-US", "fr"},
+			Country:    []string{"us", "gb"},
+			Region:     []string{"europe"},
+			Category:   []string{"business"},
+			Attributes: []string{"title", "description"},
+			Publisher:  []string{"Example News"},
+			SortBy:     "relevance",
+			Format:     "json",
+			Max:        25,
+			Page:       4,
+		},
+		(&SearchOptions{Query: "q"}).SetMax(0),
+	} {
+		canonical, err := canonicalOptions(nil, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := parseCanonicalOptions(canonical)
+		if err != nil {
+			t.Fatalf("parseCanonicalOptions(%q): %v", canonical, err)
+		}
+		if again, _ := canonicalOptions(nil, parsed); again != canonical {
+			t.Errorf("options %q decode and encode as %q", canonical, again)
+		}
+	}
+}
+
+func TestCursorVersion(t *testing.T) {
+	var c Cursor
+	if err := json.Unmarshal([]byte(`{"v":2,"endpoint":"search","options":""}`), &c); err == nil {
+		t.Error("decoded a cursor of an unknown version")
+	}
+}
@@ -0,0 +1,70 @@
+package allnewsapi
+
+import (
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// DedupeArticles returns articles without duplicates, keeping the first
+// occurrence. Articles are compared by canonical URL (see CanonicalURL);
+// articles without a URL are always kept.
+func DedupeArticles(articles []Article) []Article {
+	seen := make(map[string]bool, len(articles))
+	deduped := make([]Article, 0, len(articles))
+	for _, a := range articles {
+		if key := CanonicalURL(a.URL); key != "" {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		deduped = append(deduped, a)
+	}
+	return deduped
+}
+
+// CanonicalURL normalizes an article URL so that links to the same article
+// compare equal: the scheme and host are lowercased, a leading "www.",
+// default ports, the fragment, a trailing slash and utm_* tracking
+// parameters are removed, and the remaining query parameters are sorted.
+// Values that do not parse as absolute URLs are returned trimmed.
+func CanonicalURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if port := u.Port(); port != "" && !(scheme == "http" && port == "80") && !(scheme == "https" && port == "443") {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]" // IPv6 literal
+	}
+
+	query := u.Query()
+	for key := range query {
+		if strings.HasPrefix(strings.ToLower(key), "utm_") {
+			query.Del(key)
+		}
+	}
+
+	canonical := &url.URL{
+		Scheme:   scheme,
+		Host:     host,
+		Path:     strings.TrimSuffix(u.Path, "/"),
+		RawQuery: encodeSorted(query),
+	}
+	return canonical.String()
+}
+
+// encodeSorted encodes query with keys and values in sorted order.
+func encodeSorted(query url.Values) string {
+	for _, values := range query {
+		sort.Strings(values)
+	}
+	return query.Encode() // Encode sorts by key
+}
@@ -0,0 +1,32 @@
+package allnewsapi
+
+// WithDryRun makes a call build its request without sending it. The call
+// returns an empty result whose Meta.FinalURL is the request URL, with the
+// API key replaced by "REDACTED" and a StatusCode of 0. Use it to check the
+// parameters sent for a set of options.
+func WithDryRun() CallOption {
+	return func(cfg *callConfig) {
+		cfg.dryRun = true
+	}
+}
+
+// BuildSearchURL returns the URL a search with options would request, with
+// the API key replaced by "REDACTED". The client defaults are applied and
+// the options validated exactly as for SearchContext.
+func (c *Client) BuildSearchURL(options *SearchOptions) (string, error) {
+	return c.buildURL(searchEndpoint, options)
+}
+
+// BuildHeadlinesURL returns the URL a headlines request with options would
+// request, like BuildSearchURL.
+func (c *Client) BuildHeadlinesURL(options *SearchOptions) (string, error) {
+	return c.buildURL(headlinesEndpoint, options)
+}
+
+func (c *Client) buildURL(ep endpoint, options *SearchOptions) (string, error) {
+	params, err := c.params(options)
+	if err != nil {
+		return "", err
+	}
+	return c.requestURL(ep, params, redacted), nil
+}
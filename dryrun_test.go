@@ -0,0 +1,107 @@
+package allnewsapi
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDryRunMatchesSentURL checks that dry runs and the Build URL methods
+// report exactly the URL a real call sends, apart from the redacted key.
+func TestDryRunMatchesSentURL(t *testing.T) {
+	server, log := recordingServer(t, `{"totalArticles":0,"articles":[]}`)
+	client := newTestClient(t, server.URL, WithDefaultSearchOptions(SearchOptions{Lang: []string{"en"}, Max: 20}))
+
+	tests := []struct {
+		name    string
+		options *SearchOptions
+	}{
+		{"nil", nil},
+		{"defaults overridden", &SearchOptions{Lang: []string{"fr-CA", "de"}, Max: 5}},
+		{"query escaping", &SearchOptions{Query: `"climate change" & más/100%`}},
+		{"all lists", &SearchOptions{
+			Query:      "q",
+			Attributes: []string{"title", "description"},
+			Country:    []string{"us", "gb"},
+			Region:     []string{"europe"},
+			Category:   []string{"business"},
+			Publisher:  []string{"Example News"},
+			SortBy:     "relevance",
+			Content:    Bool(true),
+			Page:       3,
+		}},
+		{"dates", &SearchOptions{
+			StartDate: time.Date(2024, 3, 10, 12, 0, 0, 0, time.FixedZone("CET", 3600)),
+			EndDate:   "2024-03-11",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, ep := range []struct {
+				name  string
+				call  func(*SearchOptions, ...CallOption) (*SearchResponse, error)
+				build func(*SearchOptions) (string, error)
+			}{
+				{"search", client.Search, client.BuildSearchURL},
+				{"headlines", client.Headlines, client.BuildHeadlinesURL},
+			} {
+				dry, err := ep.call(tt.options, WithDryRun())
+				if err != nil {
+					t.Fatalf("%s dry run: %v", ep.name, err)
+				}
+				built, err := ep.build(tt.options)
+				if err != nil {
+					t.Fatalf("%s build: %v", ep.name, err)
+				}
+				before := len(log.all())
+				if _, err := ep.call(tt.options); err != nil {
+					t.Fatalf("%s: %v", ep.name, err)
+				}
+
+				requests := log.all()
+				if len(requests) != before+1 {
+					t.Fatalf("%s: the dry run sent a request", ep.name)
+				}
+				sent := server.URL + strings.Replace(requests[before].URL.RequestURI(), "apikey="+testAPIKey, "apikey="+redacted, 1)
+				if dry.Meta.FinalURL != sent {
+					t.Errorf("%s dry run URL = %s, want the sent URL %s", ep.name, dry.Meta.FinalURL, sent)
+				}
+				if built != sent {
+					t.Errorf("%s built URL = %s, want the sent URL %s", ep.name, built, sent)
+				}
+				if dry.Meta.StatusCode != 0 || len(dry.Articles) != 0 {
+					t.Errorf("%s dry run = %+v, want an empty result", ep.name, dry)
+				}
+			}
+		})
+	}
+}
+
+func TestDryRunCallAPIKey(t *testing.T) {
+	server, log := recordingServer(t, `{"totalArticles":0,"articles":[]}`)
+	client := newTestClient(t, server.URL)
+
+	const key = "call-key"
+	dry, err := client.SearchContext(context.Background(), &SearchOptions{Query: "q"}, WithDryRun(), WithCallAPIKey(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(dry.Meta.FinalURL, key) || !strings.Contains(dry.Meta.FinalURL, "apikey="+redacted) {
+		t.Errorf("dry run URL = %s, want the call key redacted", dry.Meta.FinalURL)
+	}
+	if len(log.all()) != 0 {
+		t.Error("the dry run sent a request")
+	}
+}
+
+func TestDryRunInvalidOptions(t *testing.T) {
+	client := newTestClient(t, "http://127.0.0.1:0")
+	options := &SearchOptions{Lang: []string{"klingon"}}
+	if _, err := client.Search(options, WithDryRun()); err == nil {
+		t.Error("dry run accepted invalid options")
+	}
+	if _, err := client.BuildSearchURL(options); err == nil {
+		t.Error("BuildSearchURL accepted invalid options")
+	}
+}
@@ -0,0 +1,144 @@
+package allnewsapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// APIError is returned when the API reports an error, either with a non-200
+// status code or with an error payload delivered alongside status 200.
+type APIError struct {
+	StatusCode int    // HTTP status code of the response
+	Message    string // Error message reported by the API, if any
+	Code       string // Error code reported by the API, if any
+	Body       []byte // Raw response body
+	RequestID  string // Request ID echoed by the server, or the one sent
+	Meta       *Meta  // Response metadata
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error (status %d): %s", e.StatusCode, e.Body)
+}
+
+// newAPIError builds an APIError from a response status and body, extracting
+// the message and code from JSON error payloads when present.
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode, Body: append([]byte(nil), body...)}
+
+	var payload errorPayload
+	if json.Unmarshal(body, &payload) == nil {
+		apiErr.Message = payload.message()
+		apiErr.Code = rawString(payload.Code)
+	}
+	return apiErr
+}
+
+// errorPayload captures the top-level keys used to tell error payloads from
+// regular responses.
+type errorPayload struct {
+	Error         json.RawMessage `json:"error"`
+	Message       json.RawMessage `json:"message"`
+	Code          json.RawMessage `json:"code"`
+	Articles      json.RawMessage `json:"articles"`
+	TotalArticles json.RawMessage `json:"totalArticles"`
+}
+
+// isError reports whether the payload looks like an error rather than a
+// result set. A response with articles or totalArticles is never an error,
+// so legitimate empty result sets are not misclassified.
+func (p *errorPayload) isError() bool {
+	if p.Articles != nil || p.TotalArticles != nil {
+		return false
+	}
+	return p.Error != nil || p.Message != nil || p.Code != nil
+}
+
+// message returns the most specific error message in the payload.
+func (p *errorPayload) message() string {
+	if p.Error != nil {
+		// The error key holds either a message or an object with one
+		var nested struct {
+			Message string `json:"message"`
+		}
+		if bytes.HasPrefix(bytes.TrimSpace(p.Error), []byte("{")) && json.Unmarshal(p.Error, &nested) == nil && nested.Message != "" {
+			return nested.Message
+		}
+		if s := rawString(p.Error); s != "" {
+			return s
+		}
+	}
+	return rawString(p.Message)
+}
+
+// detectErrorPayload returns an APIError when a successful response body is
+// an error payload.
+func detectErrorPayload(statusCode int, body []byte) error {
+	var payload errorPayload
+	if json.Unmarshal(body, &payload) != nil || !payload.isError() {
+		return nil
+	}
+	return newAPIError(statusCode, body)
+}
+
+// rawString returns a JSON string or scalar as plain text.
+func rawString(raw json.RawMessage) string {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// maxSnippetSize is the maximum number of body bytes kept in a DecodeError.
+const maxSnippetSize = 512
+
+// DecodeError is returned when a response body cannot be decoded, for
+// example when a proxy replaces the API response with an HTML page.
+type DecodeError struct {
+	StatusCode  int    // HTTP status code of the response
+	ContentType string // Content-Type header of the response
+	URL         string // Final request URL with the API key redacted
+	Snippet     string // Start of the body, truncated and sanitized
+	Err         error  // Underlying decoding error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("error parsing response (status %d, content type %q, url %s): %v; body: %q",
+		e.StatusCode, e.ContentType, e.URL, e.Err, e.Snippet)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// bodySnippet returns the start of body with the API key removed, control
+// characters replaced, and the length limited to maxSnippetSize bytes.
+func bodySnippet(body []byte, apiKey string) string {
+	s := string(body)
+	if apiKey != "" {
+		s = strings.ReplaceAll(s, apiKey, redacted)
+	}
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == utf8.RuneError {
+			return ' '
+		}
+		return r
+	}, s)
+
+	if len(s) > maxSnippetSize {
+		cut := maxSnippetSize
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		s = s[:cut] + "..."
+	}
+	return s
+}
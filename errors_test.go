@@ -0,0 +1,105 @@
+package allnewsapi
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestErrorPayloadWithStatus200(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantMessage string
+		wantCode    string
+	}{
+		{"error string", `{"error":"Invalid API key"}`, "Invalid API key", ""},
+		{"error object", `{"error":{"message":"Quota exceeded"},"code":"quota_exceeded"}`, "Quota exceeded", "quota_exceeded"},
+		{"message and code", `{"message":"Bad request","code":400}`, "Bad request", "400"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := recordingServer(t, tt.body)
+			_, err := newTestClient(t, server.URL).Search(nil)
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Search error = %v, want an APIError", err)
+			}
+			if apiErr.StatusCode != http.StatusOK || apiErr.Message != tt.wantMessage || apiErr.Code != tt.wantCode {
+				t.Errorf("APIError = {Status: %d, Message: %q, Code: %q}, want {200, %q, %q}",
+					apiErr.StatusCode, apiErr.Message, apiErr.Code, tt.wantMessage, tt.wantCode)
+			}
+			if string(apiErr.Body) != tt.body {
+				t.Errorf("Body = %s, want %s", apiErr.Body, tt.body)
+			}
+		})
+	}
+}
+
+func TestEmptyResultIsNotAnError(t *testing.T) {
+	for _, body := range []string{
+		`{"totalArticles":0,"articles":[]}`,
+		`{"totalArticles":0}`,
+		`{"articles":[],"message":"No results"}`,
+		`{"totalArticles":0,"articles":[],"error":null}`,
+	} {
+		server, _ := recordingServer(t, body)
+		resp, err := newTestClient(t, server.URL).Search(nil)
+		if err != nil {
+			t.Errorf("Search with body %s: %v", body, err)
+			continue
+		}
+		if resp.TotalArticles != 0 || len(resp.Articles) != 0 {
+			t.Errorf("body %s decoded as %d articles, total %d", body, len(resp.Articles), resp.TotalArticles)
+		}
+	}
+}
+
+func TestDecodeErrorDiagnostics(t *testing.T) {
+	// The limit of 512 bytes falls inside a two-byte rune
+	longHTML := "<html><body>x" + strings.Repeat("é", 400) + "</body></html>"
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantSnippet string
+	}{
+		{"HTML page", "text/html", "<html><body>\n<h1>502 Bad Gateway</h1>\n</body></html>", "<html><body> <h1>502 Bad Gateway</h1> </body></html>"},
+		{"empty body", "application/json", "", ""},
+		{"truncated JSON", "application/json", `{"totalArticles":3,"articles":[{"title":"cut`, `{"totalArticles":3,"articles":[{"title":"cut`},
+		{"long body", "text/html", longHTML, longHTML[:511] + "..."},
+		{"body echoing the key", "text/plain", "bad key " + testAPIKey, "bad key REDACTED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				io.WriteString(w, tt.body)
+			})
+			_, err := newTestClient(t, server.URL).Search(&SearchOptions{Query: "q"})
+
+			var decodeErr *DecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("Search error = %v, want a DecodeError", err)
+			}
+			if decodeErr.StatusCode != http.StatusOK || decodeErr.ContentType != tt.contentType {
+				t.Errorf("StatusCode %d, ContentType %q; want 200, %q", decodeErr.StatusCode, decodeErr.ContentType, tt.contentType)
+			}
+			if decodeErr.Snippet != tt.wantSnippet {
+				t.Errorf("Snippet = %q, want %q", decodeErr.Snippet, tt.wantSnippet)
+			}
+			if decodeErr.Err == nil || errors.Unwrap(decodeErr) != decodeErr.Err {
+				t.Errorf("Err = %v, want the underlying decoding error", decodeErr.Err)
+			}
+			if strings.Contains(err.Error(), testAPIKey) {
+				t.Errorf("error %q contains the API key", err)
+			}
+			if !strings.Contains(decodeErr.URL, "apikey=REDACTED") || !strings.Contains(decodeErr.URL, "q=q") {
+				t.Errorf("URL = %q, want the request URL with the key redacted", decodeErr.URL)
+			}
+		})
+	}
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"fmt"
 	"log"
-	"time"
 
 	"github.com/AllNewsAPI/go-sdk"
 )
@@ -51,4 +50,4 @@ func main() {
 		fmt.Printf("URL: %s\n", article.URL)
 		fmt.Println("---")
 	}
-}
\ No newline at end of file
+}
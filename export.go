@@ -0,0 +1,52 @@
+package allnewsapi
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// csvHeader lists the columns written by WriteCSV.
+var csvHeader = []string{
+	"publishedAt", "title", "description", "category", "country", "region",
+	"lang", "sentiment", "url", "image", "sourceName", "sourceUrl", "content",
+}
+
+// WriteCSV writes articles as CSV, with a header row. PublishedAt is written
+// in RFC 3339 format, or empty when unknown.
+func WriteCSV(w io.Writer, articles []Article) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, a := range articles {
+		publishedAt := ""
+		if !a.PublishedAt.IsZero() {
+			publishedAt = a.PublishedAt.Format(time.RFC3339)
+		}
+		record := []string{
+			publishedAt, a.Title, a.Description, a.Category, a.Country, a.Region,
+			a.Lang, a.Sentiment, a.URL, a.Image, a.Source.Name, a.Source.URL, a.Content,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteNDJSON writes articles as newline-delimited JSON, one article per
+// line.
+func WriteNDJSON(w io.Writer, articles []Article) error {
+	enc := json.NewEncoder(w)
+	for _, a := range articles {
+		if err := enc.Encode(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package allnewsapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// FanOutDims lists the values to fan a query out over. Each non-empty
+// dimension replaces the corresponding field of the base options with one of
+// its values; empty dimensions keep the base value.
+type FanOutDims struct {
+	Lang     []string
+	Country  []string
+	Region   []string
+	Category []string
+}
+
+// FanOutCombination is the set of values used by one fan-out query. Fields
+// of dimensions that were not fanned out are empty.
+type FanOutCombination struct {
+	Lang     string
+	Country  string
+	Region   string
+	Category string
+}
+
+func (c FanOutCombination) String() string {
+	var parts []string
+	for _, p := range []struct{ name, value string }{
+		{"lang", c.Lang}, {"country", c.Country}, {"region", c.Region}, {"category", c.Category},
+	} {
+		if p.value != "" {
+			parts = append(parts, p.name+"="+p.value)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// combinations returns the cartesian product of the non-empty dimensions.
+func (d FanOutDims) combinations() []FanOutCombination {
+	combos := []FanOutCombination{{}}
+	expand := func(values []string, set func(*FanOutCombination, string)) {
+		if len(values) == 0 {
+			return
+		}
+		next := make([]FanOutCombination, 0, len(combos)*len(values))
+		for _, combo := range combos {
+			for _, v := range values {
+				c := combo
+				set(&c, v)
+				next = append(next, c)
+			}
+		}
+		combos = next
+	}
+	expand(d.Lang, func(c *FanOutCombination, v string) { c.Lang = v })
+	expand(d.Country, func(c *FanOutCombination, v string) { c.Country = v })
+	expand(d.Region, func(c *FanOutCombination, v string) { c.Region = v })
+	expand(d.Category, func(c *FanOutCombination, v string) { c.Category = v })
+	return combos
+}
+
+// apply returns a copy of base restricted to the combination.
+func (c FanOutCombination) apply(base *SearchOptions) *SearchOptions {
+	options := base.Clone()
+	if options == nil {
+		options = &SearchOptions{}
+	}
+	if c.Lang != "" {
+		options.Lang = []string{c.Lang}
+	}
+	if c.Country != "" {
+		options.Country = []string{c.Country}
+	}
+	if c.Region != "" {
+		options.Region = []string{c.Region}
+	}
+	if c.Category != "" {
+		options.Category = []string{c.Category}
+	}
+	return options
+}
+
+// FanOutFailure is a fan-out query that failed.
+type FanOutFailure struct {
+	Combination FanOutCombination
+	Err         error
+}
+
+// FanOutError reports the fan-out queries that failed.
+type FanOutError struct {
+	Total  int // Number of combinations queried
+	Failed []FanOutFailure
+}
+
+func (e *FanOutError) Error() string {
+	parts := make([]string, len(e.Failed))
+	for i, f := range e.Failed {
+		parts[i] = fmt.Sprintf("%s: %v", f.Combination, f.Err)
+	}
+	return fmt.Sprintf("%d of %d fan-out queries failed: %s", len(e.Failed), e.Total, strings.Join(parts, "; "))
+}
+
+// SearchFanOut runs base once per combination of the values in dims, with at
+// most concurrency requests in flight, and merges the results. This gives
+// the union of, for example, several languages, where a single query would
+// match articles in any of them but only up to one page.
+//
+// Articles are listed in combination order (Lang varying slowest) and
+// deduplicated by canonical URL. TotalArticles is the sum of the per-query
+// totals, so articles matching several combinations are counted more than
+// once. Pagination fields are left zero.
+//
+// When some combinations fail, the merged results of the others are
+// returned along with a *FanOutError.
+func (c *Client) SearchFanOut(ctx context.Context, base *SearchOptions, dims FanOutDims, concurrency int, opts ...MultiOption) (*SearchResponse, error) {
+	combos := dims.combinations()
+	queries := make([]*SearchOptions, len(combos))
+	for i, combo := range combos {
+		queries[i] = combo.apply(base)
+	}
+
+	results, err := c.SearchMulti(ctx, queries, concurrency, opts...)
+
+	responses := make([]*SearchResponse, len(results))
+	for i, r := range results {
+		responses[i] = r.Response
+	}
+	merged := MergeResponses(responses...)
+	merged.Articles = DedupeArticles(merged.Articles)
+
+	if err != nil {
+		fanOutErr := &FanOutError{Total: len(combos)}
+		for i, r := range results {
+			if r.Err != nil {
+				fanOutErr.Failed = append(fanOutErr.Failed, FanOutFailure{Combination: combos[i], Err: r.Err})
+			}
+		}
+		return merged, fanOutErr
+	}
+	return merged, nil
+}
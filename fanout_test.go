@@ -0,0 +1,114 @@
+package allnewsapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// fanOutServer answers each search with two articles: one shared by every
+// language, linked with tracking parameters that differ per language, and
+// one specific to the language and country. Language "de" fails.
+func fanOutServer(t *testing.T) (string, *requestLog) {
+	log := &requestLog{}
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		log.add(r)
+		query := r.URL.Query()
+		lang, country := query.Get("lang"), query.Get("country")
+		if lang == "de" {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"message":"unavailable"}`))
+			return
+		}
+		fmt.Fprintf(w, `{"totalArticles":10,"currentPage":1,"nextPage":2,"articles":[`+
+			`{"title":"shared","url":"https://www.example.com/shared/?utm_source=%[1]s"},`+
+			`{"title":"%[1]s-%[2]s","url":"https://example.com/%[1]s/%[2]s"}]}`, lang, country)
+	})
+	return server.URL, log
+}
+
+func titles(articles []Article) []string {
+	out := make([]string, len(articles))
+	for i, a := range articles {
+		out[i] = a.Title
+	}
+	return out
+}
+
+func TestSearchFanOutDedupesOverlap(t *testing.T) {
+	url, log := fanOutServer(t)
+	client := newTestClient(t, url)
+
+	base := &SearchOptions{Query: "q", Lang: []string{"it"}, Category: []string{"tech"}}
+	resp, err := client.SearchFanOut(context.Background(), base, FanOutDims{Lang: []string{"en", "fr"}, Country: []string{"us", "ca"}}, 2)
+	if err != nil {
+		t.Fatalf("SearchFanOut: %v", err)
+	}
+
+	// The shared article is kept once, in combination order
+	want := "[shared en-us en-ca fr-us fr-ca]"
+	if got := fmt.Sprint(titles(resp.Articles)); got != want {
+		t.Errorf("articles = %s, want %s", got, want)
+	}
+	if resp.TotalArticles != 40 || resp.NextPage != nil || resp.CurrentPage != 0 {
+		t.Errorf("TotalArticles %d, NextPage %v, CurrentPage %d; want 40 and no pagination", resp.TotalArticles, resp.NextPage, resp.CurrentPage)
+	}
+
+	// Each combination replaces the fanned out fields and keeps the others
+	var got []string
+	for _, r := range log.all() {
+		q := r.URL.Query()
+		got = append(got, strings.Join([]string{q.Get("q"), q.Get("lang"), q.Get("country"), q.Get("category")}, " "))
+	}
+	sort.Strings(got)
+	want = "[q en ca tech q en us tech q fr ca tech q fr us tech]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("queries = %v, want %s", got, want)
+	}
+	if len(base.Lang) != 1 || base.Lang[0] != "it" {
+		t.Errorf("base options were modified: %+v", base)
+	}
+}
+
+func TestSearchFanOutFailingCombination(t *testing.T) {
+	url, _ := fanOutServer(t)
+	client := newTestClient(t, url)
+
+	resp, err := client.SearchFanOut(context.Background(), nil, FanOutDims{Lang: []string{"en", "de"}, Country: []string{"us"}}, 4)
+	var fanOutErr *FanOutError
+	if !errors.As(err, &fanOutErr) {
+		t.Fatalf("SearchFanOut error = %v, want a FanOutError", err)
+	}
+	if fanOutErr.Total != 2 || len(fanOutErr.Failed) != 1 || fanOutErr.Failed[0].Combination != (FanOutCombination{Lang: "de", Country: "us"}) {
+		t.Errorf("FanOutError = %+v, want lang=de,country=us failed out of 2", fanOutErr)
+	}
+	if !strings.HasPrefix(err.Error(), "1 of 2 fan-out queries failed: lang=de,country=us: ") {
+		t.Errorf("error = %q, want it to name the failed combination", err)
+	}
+
+	// The results of the other combination are still returned
+	if got := fmt.Sprint(titles(resp.Articles)); got != "[shared en-us]" || resp.TotalArticles != 10 {
+		t.Errorf("articles = %s with total %d, want [shared en-us] with total 10", got, resp.TotalArticles)
+	}
+}
+
+func TestFanOutCombinations(t *testing.T) {
+	tests := []struct {
+		dims FanOutDims
+		want string
+	}{
+		{FanOutDims{}, "[]"},
+		{FanOutDims{Category: []string{"a", "b"}}, "[category=a category=b]"},
+		{FanOutDims{Lang: []string{"en", "fr"}, Region: []string{"eu"}, Category: []string{"x", "y"}},
+			"[lang=en,region=eu,category=x lang=en,region=eu,category=y lang=fr,region=eu,category=x lang=fr,region=eu,category=y]"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(tt.dims.combinations()); got != tt.want {
+			t.Errorf("combinations of %+v = %s, want %s", tt.dims, got, tt.want)
+		}
+	}
+}
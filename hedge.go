@@ -0,0 +1,88 @@
+package allnewsapi
+
+import (
+	"context"
+	"time"
+)
+
+// WithHedging sends up to maxHedges duplicate requests when a request has
+// not completed within delay, and uses whichever response arrives first. The
+// slower requests are cancelled. Since every request to the API is an
+// idempotent GET this is safe, but each hedge counts against the API quota;
+// hedges are reported separately in RequestMetrics.Hedges.
+func WithHedging(delay time.Duration, maxHedges int) ClientOption {
+	return func(c *Client) {
+		c.hedgeDelay = delay
+		c.maxHedges = maxHedges
+	}
+}
+
+// fetchResult is the outcome of one hedged request.
+type fetchResult struct {
+	resp *response
+	err  error
+}
+
+// fetchHedged fetches reqURL, firing hedged requests as configured. It
+// returns the first response received and the number of hedges fired. An
+// error is returned only once every request in flight has failed.
+func (c *Client) fetchHedged(ctx context.Context, reqURL, requestID, apiKey string) (*response, int, error) {
+	if c.hedgeDelay <= 0 || c.maxHedges <= 0 {
+		resp, err := c.fetch(ctx, reqURL, requestID, apiKey)
+		return resp, 0, err
+	}
+
+	// Cancelling ctx on return stops the requests that lost the race
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Buffered so that losing requests never block on send
+	results := make(chan fetchResult, c.maxHedges+1)
+	launch := func() {
+		go func() {
+			resp, err := c.fetch(ctx, reqURL, requestID, apiKey)
+			results <- fetchResult{resp: resp, err: err}
+		}()
+	}
+
+	launch()
+	inflight, hedges := 1, 0
+
+	timer := c.clock.NewTimer(c.hedgeDelay)
+	defer timer.Stop()
+
+	var firstErr error
+	for {
+		select {
+		case <-timer.C():
+			launch()
+			inflight++
+			hedges++
+			if hedges < c.maxHedges {
+				timer.Reset(c.hedgeDelay)
+			}
+		case result := <-results:
+			inflight--
+			if result.err == nil {
+				go releaseLosers(results, inflight)
+				return result.resp, hedges, nil
+			}
+			if firstErr == nil {
+				firstErr = result.err
+			}
+			if inflight == 0 {
+				return nil, hedges, firstErr
+			}
+		}
+	}
+}
+
+// releaseLosers receives the results of the n requests that lost the race
+// and returns the bodies of those that completed anyway to the pool.
+func releaseLosers(results <-chan fetchResult, n int) {
+	for i := 0; i < n; i++ {
+		if result := <-results; result.resp != nil {
+			result.resp.releaseBody()
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package allnewsapi_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	allnewsapi "github.com/AllNewsAPI/go-sdk"
+	"github.com/AllNewsAPI/go-sdk/allnewsapitest"
+)
+
+// slowFirstServer answers the first request of every call only once its
+// context is cancelled, and the hedged requests immediately. It signals
+// every request received on started.
+type slowFirstServer struct {
+	*httptest.Server
+	started  chan struct{}
+	handlers sync.WaitGroup // handlers still running
+
+	mu   sync.Mutex
+	seen map[string]bool // request IDs received
+}
+
+func newSlowFirstServer(t *testing.T) *slowFirstServer {
+	s := &slowFirstServer{started: make(chan struct{}, 10), seen: make(map[string]bool)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.handlers.Add(1)
+		defer s.handlers.Done()
+
+		s.mu.Lock()
+		id := r.Header.Get("X-Request-ID")
+		primary := !s.seen[id]
+		s.seen[id] = true
+		s.mu.Unlock()
+
+		s.started <- struct{}{}
+		if primary {
+			<-r.Context().Done()
+			return
+		}
+		io.WriteString(w, okBody)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// hedgedCall runs a hedged search driven by clock, advancing it by delay
+// once the primary request was received, and returns the metrics and error
+// of the call.
+func hedgedCall(t *testing.T, server *slowFirstServer, clock *allnewsapitest.FakeClock, delay time.Duration, opts ...allnewsapi.ClientOption) (allnewsapi.RequestMetrics, error) {
+	t.Helper()
+	metrics := make(chan allnewsapi.RequestMetrics, 1)
+	client := newClient(t, server.URL, append([]allnewsapi.ClientOption{allnewsapi.WithHedging(delay, 1), allnewsapi.WithClock(clock),
+		allnewsapi.WithMetrics(func(m allnewsapi.RequestMetrics) { metrics <- m })}, opts...)...)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Search(nil)
+		done <- err
+	}()
+
+	<-server.started // primary
+	clock.BlockUntil(1)
+	clock.Advance(delay)
+	<-server.started // hedge
+	err := <-done
+	return <-metrics, err
+}
+
+func TestHedgeWins(t *testing.T) {
+	server := newSlowFirstServer(t)
+	clock := allnewsapitest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	m, err := hedgedCall(t, server, clock, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if m.Hedges != 1 || m.Attempts != 1 {
+		t.Errorf("Hedges = %d, Attempts = %d; want 1 and 1", m.Hedges, m.Attempts)
+	}
+	if m.Duration != 200*time.Millisecond {
+		t.Errorf("Duration = %s, want the 200ms the fake clock advanced", m.Duration)
+	}
+	if clock.Waiters() != 0 {
+		t.Errorf("%d timers still waiting after the call", clock.Waiters())
+	}
+}
+
+func TestNoHedgeWhenPrimaryIsFast(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, okBody)
+	}))
+	defer server.Close()
+
+	clock := allnewsapitest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	var hedges int
+	client := newClient(t, server.URL, allnewsapi.WithHedging(time.Second, 2), allnewsapi.WithClock(clock),
+		allnewsapi.WithMetrics(func(m allnewsapi.RequestMetrics) { hedges = m.Hedges }))
+
+	if _, err := client.Search(nil); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if hedges != 0 {
+		t.Errorf("Hedges = %d, want 0", hedges)
+	}
+	if clock.Waiters() != 0 {
+		t.Errorf("the hedge timer was not stopped: %d timers waiting", clock.Waiters())
+	}
+}
+
+// trackingTransport counts the requests that have not finished: those
+// whose round trip has not failed or whose body was not closed.
+type trackingTransport struct {
+	requests sync.WaitGroup
+}
+
+func (tt *trackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tt.requests.Add(1)
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		tt.requests.Done()
+		return nil, err
+	}
+	resp.Body = &doneBody{ReadCloser: resp.Body, done: tt.requests.Done}
+	return resp, nil
+}
+
+// doneBody calls done once when it is closed.
+type doneBody struct {
+	io.ReadCloser
+	once sync.Once
+	done func()
+}
+
+func (b *doneBody) Close() error {
+	b.once.Do(b.done)
+	return b.ReadCloser.Close()
+}
+
+func TestHedgingDoesNotLeak(t *testing.T) {
+	server := newSlowFirstServer(t)
+	clock := allnewsapitest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	transport := &trackingTransport{}
+	httpClient := &http.Client{Transport: transport}
+
+	for i := 0; i < 20; i++ {
+		if _, err := hedgedCall(t, server, clock, time.Second, allnewsapi.WithHTTPClient(httpClient)); err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+
+	// The losing requests are cancelled, which ends them on the client and
+	// ends their handlers; both waits hang if a request leaked
+	transport.requests.Wait()
+	server.handlers.Wait()
+}
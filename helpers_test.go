@@ -0,0 +1,161 @@
+package allnewsapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testAPIKey = "test-key"
+
+// newTestClient returns a client sending requests to baseURL. The client
+// is closed when the test ends.
+func newTestClient(t testing.TB, baseURL string, opts ...ClientOption) *Client {
+	t.Helper()
+	client, err := NewClient(testAPIKey, append([]ClientOption{WithBaseURL(baseURL)}, opts...)...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// newTestServer starts a server calling handler. It is closed when the test
+// ends.
+func newTestServer(t testing.TB, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// requestLog records the requests received by a test server.
+type requestLog struct {
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func (l *requestLog) add(r *http.Request) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.requests = append(l.requests, r.Clone(r.Context()))
+}
+
+// all returns the requests received so far.
+func (l *requestLog) all() []*http.Request {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]*http.Request(nil), l.requests...)
+}
+
+// urls returns the path and query of the requests received so far.
+func (l *requestLog) urls() []string {
+	var urls []string
+	for _, r := range l.all() {
+		urls = append(urls, r.URL.RequestURI())
+	}
+	return urls
+}
+
+// recordingServer starts a server answering every request with body and
+// recording the requests.
+func recordingServer(t testing.TB, body string) (*httptest.Server, *requestLog) {
+	t.Helper()
+	log := &requestLog{}
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		log.add(r)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	})
+	return server, log
+}
+
+// barrier holds goroutines calling wait until n of them are waiting, then
+// releases them together. It lets test servers keep a known number of
+// requests in flight without sleeping. A nil barrier does not wait.
+type barrier struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	n       int
+	waiting int
+	round   int // incremented each time a group is released
+}
+
+func newBarrier(n int) *barrier {
+	b := &barrier{n: n}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+func (b *barrier) wait() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	round := b.round
+	if b.waiting++; b.waiting == b.n {
+		b.waiting = 0
+		b.round++
+		b.cond.Broadcast()
+		return
+	}
+	for round == b.round {
+		b.cond.Wait()
+	}
+}
+
+// testTime is the publication time of the first article of testArticles.
+var testTime = time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+// testArticles returns n articles with distinct titles and URLs, published
+// one minute apart, newest first.
+func testArticles(n int) []Article {
+	articles := make([]Article, n)
+	for i := range articles {
+		a := &articles[i]
+		a.Title = fmt.Sprintf("Article %d", i+1)
+		a.Description = fmt.Sprintf("Description %d", i+1)
+		a.Lang = "en"
+		a.URL = fmt.Sprintf("https://news.example.com/%d", i+1)
+		a.PublishedAt = testTime.Add(-time.Duration(i) * time.Minute)
+		a.Source.Name = "Example News"
+		a.Source.URL = "https://news.example.com"
+	}
+	return articles
+}
+
+// responseBody encodes a response with the given articles and pagination.
+func responseBody(t testing.TB, resp *SearchResponse) string {
+	t.Helper()
+	body, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("encoding response: %v", err)
+	}
+	return string(body)
+}
+
+// fatResponseBody returns a response body with n articles carrying content
+// of contentSize bytes and two fields Article does not model.
+func fatResponseBody(n, contentSize int) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, `{"totalArticles":%d,"currentPage":1,"articles":[`, n)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `{"title":"Article %d","description":"Description of article %d","category":"business",`+
+			`"content":%q,"country":"us","region":"north-america","lang":"en","sentiment":"positive",`+
+			`"url":"https://news.example.com/%d","image":"https://news.example.com/%d.jpg",`+
+			`"publishedAt":"2024-03-10T12:%02d:00Z","source":{"name":"Example News","url":"https://news.example.com"},`+
+			`"author":"Reporter %d","paywall":{"type":"metered","free":3}}`,
+			i, i, strings.Repeat("lorem ipsum ", contentSize/12+1)[:contentSize], i, i, i%60, i)
+	}
+	b.WriteString(`]}`)
+	return []byte(b.String())
+}
@@ -0,0 +1,35 @@
+package allnewsapi
+
+// IndexByURL indexes articles by canonical URL (see CanonicalURL). When
+// several articles share a URL, the first one is kept. Articles without a
+// URL are left out; their number is returned as skipped.
+func IndexByURL(articles []Article) (index map[string]Article, skipped int) {
+	index = make(map[string]Article, len(articles))
+	for _, a := range articles {
+		key := CanonicalURL(a.URL)
+		if key == "" {
+			skipped++
+			continue
+		}
+		if _, ok := index[key]; !ok {
+			index[key] = a
+		}
+	}
+	return index, skipped
+}
+
+// IndexBy groups articles by the key returned by keyFn, keeping the order of
+// articles within each group. Articles with an empty key are left out; their
+// number is returned as skipped.
+func IndexBy(articles []Article, keyFn func(Article) string) (index map[string][]Article, skipped int) {
+	index = make(map[string][]Article)
+	for _, a := range articles {
+		key := keyFn(a)
+		if key == "" {
+			skipped++
+			continue
+		}
+		index[key] = append(index[key], a)
+	}
+	return index, skipped
+}
@@ -0,0 +1,61 @@
+package allnewsapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIndexByURL(t *testing.T) {
+	articles := []Article{
+		{Title: "first", URL: "https://www.example.com/a/?utm_source=feed"},
+		{Title: "no url"},
+		{Title: "duplicate", URL: "HTTPS://example.com/a"},
+		{Title: "blank url", URL: "   "},
+		{Title: "other", URL: "https://example.com/b"},
+		{Title: "exact duplicate", URL: "https://example.com/b"},
+	}
+
+	index, skipped := IndexByURL(articles)
+	if skipped != 2 {
+		t.Errorf("skipped = %d, want the 2 articles without a URL", skipped)
+	}
+	want := map[string]string{
+		"https://example.com/a": "first",
+		"https://example.com/b": "other",
+	}
+	got := make(map[string]string, len(index))
+	for key, a := range index {
+		got[key] = a.Title
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("index = %v, want the first article of each URL: %v", got, want)
+	}
+
+	if index, skipped := IndexByURL(nil); len(index) != 0 || skipped != 0 {
+		t.Errorf("IndexByURL(nil) = %v, %d, want an empty index", index, skipped)
+	}
+}
+
+func TestIndexBy(t *testing.T) {
+	articles := []Article{
+		{Title: "a1", Lang: "en"},
+		{Title: "b1", Lang: "fr"},
+		{Title: "none"},
+		{Title: "a2", Lang: "en"},
+		{Title: "a2", Lang: "en"},
+	}
+
+	index, skipped := IndexBy(articles, func(a Article) string { return a.Lang })
+	if skipped != 1 {
+		t.Errorf("skipped = %d, want 1", skipped)
+	}
+	got := make(map[string][]string, len(index))
+	for key, group := range index {
+		got[key] = titles(group)
+	}
+	// Duplicates are kept, in their original order
+	want := map[string][]string{"en": {"a1", "a2", "a2"}, "fr": {"b1"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("index = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,93 @@
+package allnewsapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// iso639Alpha3 maps common ISO 639-2 and 639-3 codes, including the
+// bibliographic variants, to their ISO 639-1 equivalent.
+var iso639Alpha3 = map[string]string{
+	"ara": "ar", "ben": "bn", "bul": "bg", "ces": "cs", "cze": "cs",
+	"chi": "zh", "dan": "da", "deu": "de", "dut": "nl", "ell": "el",
+	"eng": "en", "est": "et", "fas": "fa", "per": "fa", "fin": "fi",
+	"fra": "fr", "fre": "fr", "ger": "de", "gre": "el", "heb": "he",
+	"hin": "hi", "hrv": "hr", "hun": "hu", "ind": "id", "ita": "it",
+	"jpn": "ja", "kor": "ko", "lav": "lv", "lit": "lt", "msa": "ms",
+	"may": "ms", "nld": "nl", "nor": "no", "pol": "pl", "por": "pt",
+	"ron": "ro", "rum": "ro", "rus": "ru", "slk": "sk", "slo": "sk",
+	"slv": "sl", "spa": "es", "srp": "sr", "swe": "sv", "tha": "th",
+	"tur": "tr", "ukr": "uk", "urd": "ur", "vie": "vi", "zho": "zh",
+}
+
+// NormalizeLang returns the lowercase ISO 639-1 code of the base language of
+// a BCP 47 tag, for example "pt" for "pt-BR" or "zh" for "zh-Hant-TW".
+// Underscores are accepted as separators. Three-letter base languages are
+// mapped to their two-letter code when they have one; tags without a
+// two-letter equivalent, private use and malformed tags return an error.
+func NormalizeLang(tag string) (string, error) {
+	subtags := strings.Split(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"), "-")
+	for _, s := range subtags {
+		if len(s) == 0 || len(s) > 8 || !isAlphanumeric(s) {
+			return "", fmt.Errorf("malformed language tag %q", tag)
+		}
+	}
+
+	base := strings.ToLower(subtags[0])
+	if !isLetters(base) {
+		return "", fmt.Errorf("malformed language tag %q", tag)
+	}
+	switch len(base) {
+	case 2:
+		return base, nil
+	case 3:
+		if code, ok := iso639Alpha3[base]; ok {
+			return code, nil
+		}
+	}
+	return "", fmt.Errorf("language tag %q has no ISO 639-1 code", tag)
+}
+
+// normalizeLangs normalizes every tag, reporting all unmappable ones.
+func normalizeLangs(tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	var invalid []string
+	for _, tag := range tags {
+		code, err := NormalizeLang(tag)
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("%q", tag))
+			continue
+		}
+		if !seen[code] {
+			seen[code] = true
+			normalized = append(normalized, code)
+		}
+	}
+	if len(invalid) > 0 {
+		return nil, fmt.Errorf("Lang has unsupported language tags: %s", strings.Join(invalid, ", "))
+	}
+	return normalized, nil
+}
+
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func isLetters(s string) bool {
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
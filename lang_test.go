@@ -0,0 +1,91 @@
+package allnewsapi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeLang(t *testing.T) {
+	tests := []struct {
+		tag     string
+		want    string
+		wantErr string
+	}{
+		// Already normalized
+		{"en", "en", ""},
+		{"pt", "pt", ""},
+
+		// Case and whitespace
+		{"EN", "en", ""},
+		{" fr ", "fr", ""},
+
+		// Regions
+		{"en-US", "en", ""},
+		{"pt_BR", "pt", ""},
+		{"es-419", "es", ""},
+
+		// Scripts, with and without a region
+		{"zh-Hant", "zh", ""},
+		{"zh-Hant-TW", "zh", ""},
+		{"sr_Latn_RS", "sr", ""},
+
+		// Three-letter codes, including bibliographic variants
+		{"eng", "en", ""},
+		{"fre", "fr", ""},
+		{"fra-CA", "fr", ""},
+		{"ZHO-Hans", "zh", ""},
+
+		{"haw", "", "no ISO 639-1 code"},
+		{"x-private", "", "no ISO 639-1 code"},
+		{"english", "", "no ISO 639-1 code"},
+		{"", "", "malformed"},
+		{"en-", "", "malformed"},
+		{"en--US", "", "malformed"},
+		{"en US", "", "malformed"},
+		{"e1", "", "malformed"},
+		{"en-toolongsubtag", "", "malformed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, err := NormalizeLang(tt.tag)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("NormalizeLang(%q) = %q, %v, want a %q error", tt.tag, got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("NormalizeLang(%q) = %q, %v, want %q", tt.tag, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeLangs(t *testing.T) {
+	got, err := normalizeLangs([]string{"en-US", "fr", "eng", "EN", "pt_BR"})
+	if err != nil || !reflect.DeepEqual(got, []string{"en", "fr", "pt"}) {
+		t.Errorf("normalizeLangs = %q, %v, want the distinct codes in order", got, err)
+	}
+
+	if got, err := normalizeLangs(nil); got != nil || err != nil {
+		t.Errorf("normalizeLangs(nil) = %q, %v, want nil", got, err)
+	}
+
+	// Every invalid tag is reported
+	_, err = normalizeLangs([]string{"en", "klingon", "x-1", "de"})
+	if err == nil || err.Error() != `Lang has unsupported language tags: "klingon", "x-1"` {
+		t.Errorf("normalizeLangs error = %v, want both invalid tags", err)
+	}
+}
+
+func TestSearchNormalizesLang(t *testing.T) {
+	server, log := recordingServer(t, `{"totalArticles":0,"articles":[]}`)
+	client := newTestClient(t, server.URL)
+	if _, err := client.Search(&SearchOptions{Lang: []string{"en-GB", "zh-Hant-TW", "en"}}); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if got := log.all()[0].URL.Query().Get("lang"); got != "en,zh" {
+		t.Errorf("lang = %q, want en,zh", got)
+	}
+}
@@ -0,0 +1,26 @@
+package allnewsapi
+
+import (
+	"net/http"
+	"time"
+)
+
+// Meta describes the HTTP response a result or APIError was decoded from.
+type Meta struct {
+	StatusCode int
+	Header     http.Header   // Copy of the response headers, safe to modify
+	FinalURL   string        // URL after redirects, with the API key redacted
+	Duration   time.Duration // Time from sending the request to reading the body
+	BodySize   int64         // Size of the decoded body in bytes
+}
+
+// meta returns the Meta of r.
+func (r *response) meta() *Meta {
+	return &Meta{
+		StatusCode: r.statusCode,
+		Header:     r.header,
+		FinalURL:   redactURL(r.url, r.apiKey),
+		Duration:   r.duration,
+		BodySize:   r.bodySize,
+	}
+}
@@ -0,0 +1,30 @@
+package allnewsapi
+
+import "time"
+
+// RequestMetrics describes a completed call to the API, including all of
+// its retries and hedged requests.
+type RequestMetrics struct {
+	Endpoint   string // Endpoint name, e.g. "search"
+	RequestID  string // Request ID sent in the X-Request-ID header
+	Attempts   int    // Number of attempts, including retries
+	Hedges     int    // Number of hedged requests fired
+	StatusCode int    // Status code of the last response, 0 if none
+
+	// Body sizes of the last response. CompressedBytes is only reported
+	// when WithCompression(true) is used and the server compressed the body.
+	CompressedBytes   int64
+	UncompressedBytes int64
+
+	Duration time.Duration // Total time spent on the call
+	Err      error         // Error returned to the caller, if any
+}
+
+// WithMetrics registers a callback invoked after every call to the API. The
+// callback runs synchronously on the calling goroutine and should return
+// quickly.
+func WithMetrics(fn func(RequestMetrics)) ClientOption {
+	return func(c *Client) {
+		c.metrics = fn
+	}
+}
@@ -0,0 +1,141 @@
+package allnewsapi
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// MultiResult is the outcome of one query run by SearchMulti.
+type MultiResult struct {
+	Options  *SearchOptions  // Query as passed to SearchMulti
+	Response *SearchResponse // nil when Err is set
+	Err      error
+}
+
+// MultiError reports the queries of a batch that failed.
+type MultiError struct {
+	Total  int           // Number of queries in the batch
+	Failed map[int]error // Errors by query index
+}
+
+func (e *MultiError) Error() string {
+	first := -1
+	for i := range e.Failed {
+		if first < 0 || i < first {
+			first = i
+		}
+	}
+	return fmt.Sprintf("%d of %d queries failed, first: query %d: %v", len(e.Failed), e.Total, first, e.Failed[first])
+}
+
+// MultiOption configures SearchMulti.
+type MultiOption func(*multiConfig)
+
+type multiConfig struct {
+	failFast bool
+	callOpts []CallOption
+}
+
+// WithFailFast cancels the remaining queries of a batch as soon as one
+// fails. Cancelled queries report the context error.
+func WithFailFast() MultiOption {
+	return func(c *multiConfig) {
+		c.failFast = true
+	}
+}
+
+// WithMultiCallOptions applies call options to every query of a batch.
+func WithMultiCallOptions(callOpts ...CallOption) MultiOption {
+	return func(c *multiConfig) {
+		c.callOpts = append(c.callOpts, callOpts...)
+	}
+}
+
+// SearchMulti runs queries against the search endpoint with at most
+// concurrency requests in flight. Results are in the order of queries. A
+// failing query does not cancel the others unless WithFailFast is set; when
+// any query fails the error is a *MultiError and the results of the other
+// queries are still returned.
+//
+// Each query goes through SearchContext, so retries and backoff on rate
+// limit responses apply per query. Keep concurrency within the number of
+// parallel requests allowed by your plan.
+func (c *Client) SearchMulti(ctx context.Context, queries []*SearchOptions, concurrency int, opts ...MultiOption) ([]MultiResult, error) {
+	var cfg multiConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	results := make([]MultiResult, len(queries))
+	err := runConcurrent(ctx, len(queries), concurrency, cfg.failFast, func(ctx context.Context, i int) error {
+		resp, err := c.SearchContext(ctx, queries[i], cfg.callOpts...)
+		results[i] = MultiResult{Options: queries[i], Response: resp, Err: err}
+		return err
+	})
+
+	// Queries skipped after a fail-fast cancellation never ran
+	if multiErr, ok := err.(*MultiError); ok {
+		for i, qErr := range multiErr.Failed {
+			if results[i].Err == nil {
+				results[i] = MultiResult{Options: queries[i], Err: qErr}
+			}
+		}
+	}
+	return results, err
+}
+
+// runConcurrent calls fn for every index in [0, n) with at most concurrency
+// calls in flight. Failures are collected into a *MultiError. With failFast,
+// the context passed to fn is cancelled after the first failure and indexes
+// not started yet fail with the context error without calling fn.
+func runConcurrent(ctx context.Context, n, concurrency int, failFast bool, fn func(ctx context.Context, i int) error) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > n {
+		concurrency = n
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	failed := map[int]error{}
+	fail := func(i int, err error) {
+		mu.Lock()
+		failed[i] = err
+		mu.Unlock()
+		if failFast {
+			cancel()
+		}
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if failFast && ctx.Err() != nil {
+					fail(i, ctx.Err())
+					continue
+				}
+				if err := fn(ctx, i); err != nil {
+					fail(i, err)
+				}
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if len(failed) == 0 {
+		return nil
+	}
+	return &MultiError{Total: n, Failed: failed}
+}
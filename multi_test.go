@@ -0,0 +1,186 @@
+package allnewsapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// queryServer answers each search with one article titled after the query.
+// Queries starting with "fail" get a 400, and a query "a<b" is answered
+// only once the query "b" was. Requests wait on hold, when set, before being
+// answered. It records the highest number of requests in flight.
+type queryServer struct {
+	requests    int32
+	inflight    int32
+	maxInflight int32
+	hold        *barrier
+
+	mu       sync.Mutex
+	answered map[string]chan struct{} // closed once the query was answered
+}
+
+func newQueryServer(t *testing.T) (*queryServer, string) {
+	s := &queryServer{answered: make(map[string]chan struct{})}
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&s.requests, 1)
+		n := atomic.AddInt32(&s.inflight, 1)
+		defer atomic.AddInt32(&s.inflight, -1)
+		for {
+			max := atomic.LoadInt32(&s.maxInflight)
+			if n <= max || atomic.CompareAndSwapInt32(&s.maxInflight, max, n) {
+				break
+			}
+		}
+		s.hold.wait()
+
+		q := r.URL.Query().Get("q")
+		name := q
+		if i := strings.Index(q, "<"); i >= 0 {
+			name = q[:i]
+			<-s.answeredChan(q[i+1:])
+		}
+		defer close(s.answeredChan(name))
+
+		if strings.HasPrefix(q, "fail") {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"message":"bad query %s"}`, q)
+			return
+		}
+		fmt.Fprintf(w, `{"totalArticles":1,"articles":[{"title":%q}]}`, q)
+	})
+	return s, server.URL
+}
+
+// answeredChan returns the channel closed once the query named name was
+// answered.
+func (s *queryServer) answeredChan(name string) chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ch, ok := s.answered[name]
+	if !ok {
+		ch = make(chan struct{})
+		s.answered[name] = ch
+	}
+	return ch
+}
+
+func queries(qs ...string) []*SearchOptions {
+	options := make([]*SearchOptions, len(qs))
+	for i, q := range qs {
+		options[i] = &SearchOptions{Query: q}
+	}
+	return options
+}
+
+func TestSearchMultiOrder(t *testing.T) {
+	_, url := newQueryServer(t)
+	client := newTestClient(t, url)
+
+	// Earlier queries finish last
+	qs := queries("a<b", "b<c", "c<d", "d<e", "e")
+	results, err := client.SearchMulti(context.Background(), qs, 5)
+	if err != nil {
+		t.Fatalf("SearchMulti: %v", err)
+	}
+	for i, r := range results {
+		if r.Options != qs[i] || r.Err != nil || r.Response.Articles[0].Title != qs[i].Query {
+			t.Errorf("result %d = %+v, want the response to %q", i, r, qs[i].Query)
+		}
+	}
+}
+
+func TestSearchMultiConcurrencyBound(t *testing.T) {
+	for _, concurrency := range []int{1, 3, 20} {
+		t.Run(fmt.Sprint(concurrency), func(t *testing.T) {
+			want := int32(concurrency)
+			if want > 12 {
+				want = 12
+			}
+			// Requests are answered in groups of want, so that many are
+			// in flight at once
+			s, url := newQueryServer(t)
+			s.hold = newBarrier(int(want))
+			client := newTestClient(t, url)
+			var qs []*SearchOptions
+			for i := 0; i < 12; i++ {
+				qs = append(qs, &SearchOptions{Query: fmt.Sprint(i)})
+			}
+
+			if _, err := client.SearchMulti(context.Background(), qs, concurrency); err != nil {
+				t.Fatalf("SearchMulti: %v", err)
+			}
+			requests, max := atomic.LoadInt32(&s.requests), atomic.LoadInt32(&s.maxInflight)
+			if max != want || requests != 12 {
+				t.Errorf("%d requests with at most %d in flight, want 12 with %d", requests, max, want)
+			}
+		})
+	}
+}
+
+func TestSearchMultiPartialFailure(t *testing.T) {
+	_, url := newQueryServer(t)
+	client := newTestClient(t, url)
+
+	qs := queries("a", "fail-1", "b", "fail-2", "c")
+	results, err := client.SearchMulti(context.Background(), qs, 2)
+	var multiErr *MultiError
+	if !errors.As(err, &multiErr) {
+		t.Fatalf("SearchMulti error = %v, want a MultiError", err)
+	}
+	if multiErr.Total != 5 || len(multiErr.Failed) != 2 || multiErr.Failed[1] == nil || multiErr.Failed[3] == nil {
+		t.Errorf("MultiError = %+v, want queries 1 and 3 failed out of 5", multiErr)
+	}
+	if !strings.HasPrefix(err.Error(), "2 of 5 queries failed, first: query 1: ") {
+		t.Errorf("error = %q, want it to name query 1 first", err)
+	}
+
+	for i, r := range results {
+		failed := strings.HasPrefix(qs[i].Query, "fail")
+		if failed != (r.Err != nil) || failed != (r.Response == nil) {
+			t.Errorf("result %d = %+v, want failed: %v", i, r, failed)
+		}
+		var apiErr *APIError
+		if failed && (!errors.As(r.Err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest) {
+			t.Errorf("result %d error = %v, want the 400", i, r.Err)
+		}
+	}
+}
+
+func TestSearchMultiFailFast(t *testing.T) {
+	s, url := newQueryServer(t)
+	client := newTestClient(t, url)
+
+	results, err := client.SearchMulti(context.Background(), queries("fail", "a", "b", "c"), 1, WithFailFast())
+	var multiErr *MultiError
+	if !errors.As(err, &multiErr) || len(multiErr.Failed) != 4 {
+		t.Fatalf("SearchMulti error = %v, want all 4 queries failed", err)
+	}
+	if n := atomic.LoadInt32(&s.requests); n != 1 {
+		t.Errorf("sent %d requests, want only the failing one", n)
+	}
+	for i, r := range results[1:] {
+		if !errors.Is(r.Err, context.Canceled) || r.Options == nil {
+			t.Errorf("result %d = %+v, want a cancelled query", i+1, r)
+		}
+	}
+}
+
+func TestRunConcurrentEmpty(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	err := runConcurrent(context.Background(), 0, 4, false, func(context.Context, int) error {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		return nil
+	})
+	if err != nil || calls != 0 {
+		t.Errorf("runConcurrent over nothing = %v with %d calls", err, calls)
+	}
+}
@@ -0,0 +1,193 @@
+package allnewsapi
+
+import "fmt"
+
+// SearchOptions contains all possible parameters for the search endpoint.
+type SearchOptions struct {
+	Query      string      // Search query
+	StartDate  interface{} // string or time.Time
+	EndDate    interface{} // string or time.Time
+	Content    *bool       // Whether to include full content
+	Lang       []string    // Languages to filter by, as ISO 639-1 codes or BCP 47 tags
+	Country    []string    // Countries to filter by
+	Region     []string    // Regions to filter by
+	Category   []string    // Categories to filter by
+	Max        int         // Maximum number of results (1-100), 0 means unset unless set with SetMax
+	Attributes []string    // Attributes to search in (title, description, content)
+	Page       int         // Page number for pagination, 0 means unset unless set with SetPage
+	SortBy     string      // Sort by 'publishedAt' or 'relevance'
+	Publisher  []string    // Publishers to filter by
+	Format     string      // Response format (json, csv, xlsx)
+
+	maxSet  bool // Max was set explicitly and is sent even when zero
+	pageSet bool // Page was set explicitly and is sent even when zero
+}
+
+// Bool returns a pointer to b, for use with optional fields such as Content.
+func Bool(b bool) *bool {
+	return &b
+}
+
+// Int returns a pointer to i.
+func Int(i int) *int {
+	return &i
+}
+
+// String returns a pointer to s.
+func String(s string) *string {
+	return &s
+}
+
+// SetMax sets Max and marks it as explicitly set, so the value is sent to the
+// API even when it is zero. An explicit zero passes Validate and is sent as
+// max=0, leaving the API to apply its own default. It returns o to allow
+// chaining.
+func (o *SearchOptions) SetMax(max int) *SearchOptions {
+	o.Max = max
+	o.maxSet = true
+	return o
+}
+
+// SetPage sets Page and marks it as explicitly set, so the value is sent to
+// the API even when it is zero. An explicit zero passes Validate and is sent
+// as page=0. It returns o to allow chaining.
+func (o *SearchOptions) SetPage(page int) *SearchOptions {
+	o.Page = page
+	o.pageSet = true
+	return o
+}
+
+// MaxSet reports whether Max has a value, either non-zero or set explicitly
+// with SetMax.
+func (o *SearchOptions) MaxSet() bool {
+	return o != nil && (o.Max != 0 || o.maxSet)
+}
+
+// PageSet reports whether Page has a value, either non-zero or set explicitly
+// with SetPage.
+func (o *SearchOptions) PageSet() bool {
+	return o != nil && (o.Page != 0 || o.pageSet)
+}
+
+// Validate checks the options for values the API would reject. It is called
+// by Search and Headlines before any network request is made. Max must be
+// between 1 and 100 and Page at least 1, except that either may be an
+// explicit zero set with SetMax or SetPage.
+func (o *SearchOptions) Validate() error {
+	if o == nil {
+		return nil
+	}
+	if o.Max < 0 || o.Max > 100 {
+		return fmt.Errorf("Max must be between 1 and 100, got %d", o.Max)
+	}
+	if o.Page < 0 {
+		return fmt.Errorf("Page must be at least 1, got %d", o.Page)
+	}
+	if _, err := normalizeLangs(o.Lang); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Clone returns a deep copy of o. Slices and the Content pointer are copied
+// so the clone can be modified without affecting o. Clone returns nil when o
+// is nil.
+func (o *SearchOptions) Clone() *SearchOptions {
+	if o == nil {
+		return nil
+	}
+
+	clone := *o
+	if o.Content != nil {
+		content := *o.Content
+		clone.Content = &content
+	}
+	clone.Lang = copyStrings(o.Lang)
+	clone.Country = copyStrings(o.Country)
+	clone.Region = copyStrings(o.Region)
+	clone.Category = copyStrings(o.Category)
+	clone.Attributes = copyStrings(o.Attributes)
+	clone.Publisher = copyStrings(o.Publisher)
+
+	return &clone
+}
+
+// Merge returns a new SearchOptions with overlay applied over o:
+//
+//   - non-zero scalar fields in overlay (Query, dates, Content, Max, Page,
+//     SortBy, Format) override the value in o; Max and Page also override
+//     when set explicitly with SetMax or SetPage
+//   - a nil slice in overlay means "no change" and keeps the value from o
+//   - a non-nil slice in overlay replaces the value from o; an empty slice
+//     therefore clears the filter
+//
+// Neither o nor overlay is modified and the result shares no memory with
+// them. Merge is safe to call on a nil receiver and returns nil only when
+// both o and overlay are nil.
+func (o *SearchOptions) Merge(overlay *SearchOptions) *SearchOptions {
+	if o == nil {
+		return overlay.Clone()
+	}
+
+	merged := o.Clone()
+	if overlay == nil {
+		return merged
+	}
+
+	if overlay.Query != "" {
+		merged.Query = overlay.Query
+	}
+	if overlay.StartDate != nil {
+		merged.StartDate = overlay.StartDate
+	}
+	if overlay.EndDate != nil {
+		merged.EndDate = overlay.EndDate
+	}
+	if overlay.Content != nil {
+		content := *overlay.Content
+		merged.Content = &content
+	}
+	if overlay.Lang != nil {
+		merged.Lang = copyStrings(overlay.Lang)
+	}
+	if overlay.Country != nil {
+		merged.Country = copyStrings(overlay.Country)
+	}
+	if overlay.Region != nil {
+		merged.Region = copyStrings(overlay.Region)
+	}
+	if overlay.Category != nil {
+		merged.Category = copyStrings(overlay.Category)
+	}
+	if overlay.MaxSet() {
+		merged.Max = overlay.Max
+		merged.maxSet = overlay.maxSet
+	}
+	if overlay.Attributes != nil {
+		merged.Attributes = copyStrings(overlay.Attributes)
+	}
+	if overlay.PageSet() {
+		merged.Page = overlay.Page
+		merged.pageSet = overlay.pageSet
+	}
+	if overlay.SortBy != "" {
+		merged.SortBy = overlay.SortBy
+	}
+	if overlay.Publisher != nil {
+		merged.Publisher = copyStrings(overlay.Publisher)
+	}
+	if overlay.Format != "" {
+		merged.Format = overlay.Format
+	}
+
+	return merged
+}
+
+// copyStrings returns a copy of s, preserving the difference between nil and
+// empty slices.
+func copyStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(s)), s...)
+}
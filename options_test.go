@@ -0,0 +1,274 @@
+package allnewsapi
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCloneIsDeep(t *testing.T) {
+	orig := &SearchOptions{
+		Query:      "q",
+		StartDate:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Content:    Bool(true),
+		Lang:       []string{"en"},
+		Country:    []string{"us"},
+		Region:     []string{"europe"},
+		Category:   []string{"tech"},
+		Attributes: []string{"title"},
+		Publisher:  []string{"bbc.co.uk"},
+	}
+	orig.SetMax(10).SetPage(2)
+
+	clone := orig.Clone()
+	if !reflect.DeepEqual(clone, orig) {
+		t.Fatalf("Clone() = %+v, want %+v", clone, orig)
+	}
+
+	*clone.Content = false
+	clone.Lang[0] = "fr"
+	clone.Country[0] = "gb"
+	clone.Region[0] = "asia"
+	clone.Category[0] = "sports"
+	clone.Attributes[0] = "content"
+	clone.Publisher[0] = "cnn.com"
+	if !*orig.Content || orig.Lang[0] != "en" || orig.Country[0] != "us" || orig.Region[0] != "europe" ||
+		orig.Category[0] != "tech" || orig.Attributes[0] != "title" || orig.Publisher[0] != "bbc.co.uk" {
+		t.Errorf("modifying the clone changed the original: %+v", orig)
+	}
+
+	if (*SearchOptions)(nil).Clone() != nil {
+		t.Error("Clone of nil options is not nil")
+	}
+	if empty := (&SearchOptions{Lang: []string{}}).Clone(); empty.Lang == nil {
+		t.Error("Clone turned an empty slice into nil")
+	}
+}
+
+// TestCloneConcurrent mutates clones of shared options from several
+// goroutines; run with -race.
+func TestCloneConcurrent(t *testing.T) {
+	shared := &SearchOptions{Query: "shared", Content: Bool(true), Lang: []string{"en", "fr"}, Publisher: []string{"a", "b"}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				clone := shared.Clone()
+				clone.Query = strings.Repeat("x", i)
+				clone.Lang[0] = "de"
+				clone.Lang = append(clone.Lang, "es")
+				clone.Publisher[1] = "c"
+				*clone.Content = false
+				clone.SetPage(j + 1)
+
+				merged := shared.Merge(clone)
+				merged.Country = append(merged.Country, "us")
+				merged.Lang[1] = "it"
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	want := &SearchOptions{Query: "shared", Content: Bool(true), Lang: []string{"en", "fr"}, Publisher: []string{"a", "b"}}
+	if !reflect.DeepEqual(shared, want) {
+		t.Errorf("shared options changed: %+v", shared)
+	}
+}
+
+func TestMergeSlices(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    []string
+		overlay []string
+		want    []string
+	}{
+		{"nil overlay keeps base", []string{"en"}, nil, []string{"en"}},
+		{"empty overlay clears base", []string{"en"}, []string{}, []string{}},
+		{"overlay replaces base", []string{"en", "fr"}, []string{"de"}, []string{"de"}},
+		{"overlay over nil base", nil, []string{"de"}, []string{"de"}},
+		{"nil over nil", nil, nil, nil},
+		{"empty over nil", nil, []string{}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := &SearchOptions{Lang: tt.base, Country: tt.base, Region: tt.base, Category: tt.base, Attributes: tt.base, Publisher: tt.base}
+			overlay := &SearchOptions{Lang: tt.overlay, Country: tt.overlay, Region: tt.overlay, Category: tt.overlay, Attributes: tt.overlay, Publisher: tt.overlay}
+			merged := base.Merge(overlay)
+			for name, got := range map[string][]string{
+				"Lang": merged.Lang, "Country": merged.Country, "Region": merged.Region,
+				"Category": merged.Category, "Attributes": merged.Attributes, "Publisher": merged.Publisher,
+			} {
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("%s = %#v, want %#v", name, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeScalars(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	base := &SearchOptions{Query: "base", StartDate: start, Content: Bool(true), Max: 10, Page: 2, SortBy: "relevance", Format: "json"}
+
+	tests := []struct {
+		name    string
+		overlay *SearchOptions
+		want    *SearchOptions
+	}{
+		{"nil overlay", nil, base},
+		{"zero overlay", &SearchOptions{}, base},
+		{
+			"non-zero overlay",
+			&SearchOptions{Query: "over", EndDate: "2024-02-01", Content: Bool(false), Max: 50, Page: 3, SortBy: "publishedAt", Format: "csv"},
+			&SearchOptions{Query: "over", StartDate: start, EndDate: "2024-02-01", Content: Bool(false), Max: 50, Page: 3, SortBy: "publishedAt", Format: "csv"},
+		},
+		{
+			"explicit zero page overrides",
+			(&SearchOptions{}).SetPage(0),
+			(&SearchOptions{Query: "base", StartDate: start, Content: Bool(true), Max: 10, SortBy: "relevance", Format: "json"}).SetPage(0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := base.Merge(tt.overlay); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Merge() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if got := (*SearchOptions)(nil).Merge(nil); got != nil {
+		t.Errorf("nil.Merge(nil) = %+v, want nil", got)
+	}
+	overlay := &SearchOptions{Lang: []string{"en"}}
+	if got := (*SearchOptions)(nil).Merge(overlay); !reflect.DeepEqual(got, overlay) || &got.Lang[0] == &overlay.Lang[0] {
+		t.Errorf("nil.Merge(overlay) = %+v, want a copy of %+v", got, overlay)
+	}
+}
+
+func TestMergeDoesNotShareMemory(t *testing.T) {
+	base := &SearchOptions{Lang: []string{"en"}, Content: Bool(true)}
+	overlay := &SearchOptions{Country: []string{"us"}, Content: Bool(false)}
+	merged := base.Merge(overlay)
+
+	merged.Lang[0] = "fr"
+	merged.Country[0] = "gb"
+	*merged.Content = true
+	if base.Lang[0] != "en" || overlay.Country[0] != "us" || *overlay.Content {
+		t.Errorf("modifying the merged options changed the inputs: base %+v, overlay %+v", base, overlay)
+	}
+}
+
+func TestSetMaxSetPageQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *SearchOptions
+		want    string
+	}{
+		{"unset", &SearchOptions{}, ""},
+		{"zero fields", &SearchOptions{Max: 0, Page: 0}, ""},
+		{"non-zero fields", &SearchOptions{Max: 25, Page: 2}, "max=25&page=2"},
+		{"explicit zero max", (&SearchOptions{}).SetMax(0), "max=0"},
+		{"explicit zero page", (&SearchOptions{}).SetPage(0), "page=0"},
+		{"explicit values", (&SearchOptions{}).SetMax(25).SetPage(2), "max=25&page=2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := buildParams(tt.options)
+			if err != nil {
+				t.Fatalf("buildParams: %v", err)
+			}
+			if got := params.Encode(); got != tt.want {
+				t.Errorf("query = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxSetPageSet(t *testing.T) {
+	var nilOptions *SearchOptions
+	if nilOptions.MaxSet() || nilOptions.PageSet() {
+		t.Error("nil options report Max or Page as set")
+	}
+	if o := (&SearchOptions{}); o.MaxSet() || o.PageSet() {
+		t.Error("zero options report Max or Page as set")
+	}
+	if o := (&SearchOptions{}).SetMax(0).SetPage(0); !o.MaxSet() || !o.PageSet() {
+		t.Error("explicit zero values are not reported as set")
+	}
+	if o := (&SearchOptions{Max: 3, Page: 4}); !o.MaxSet() || !o.PageSet() {
+		t.Error("non-zero values are not reported as set")
+	}
+}
+
+func TestValidateBoundaries(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *SearchOptions
+		wantErr string
+	}{
+		{"nil", nil, ""},
+		{"unset", &SearchOptions{}, ""},
+		{"max 1", &SearchOptions{Max: 1}, ""},
+		{"max 100", &SearchOptions{Max: 100}, ""},
+		{"max 101", &SearchOptions{Max: 101}, "Max must be between 1 and 100, got 101"},
+		{"max negative", &SearchOptions{Max: -1}, "Max must be between 1 and 100, got -1"},
+		{"max explicit 0", (&SearchOptions{}).SetMax(0), ""},
+		{"max explicit negative", (&SearchOptions{}).SetMax(-1), "Max must be between 1 and 100, got -1"},
+		{"max explicit 1", (&SearchOptions{}).SetMax(1), ""},
+		{"page 1", &SearchOptions{Page: 1}, ""},
+		{"page 101", &SearchOptions{Page: 101}, ""},
+		{"page negative", &SearchOptions{Page: -3}, "Page must be at least 1, got -3"},
+		{"page explicit 0", (&SearchOptions{}).SetPage(0), ""},
+		{"page explicit negative", (&SearchOptions{}).SetPage(-1), "Page must be at least 1, got -1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Validate() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateBeforeRequest(t *testing.T) {
+	server, log := recordingServer(t, `{"totalArticles":0,"articles":[]}`)
+	client := newTestClient(t, server.URL)
+
+	for _, options := range []*SearchOptions{{Max: 101}, {Max: -1}, {Page: -1}, (&SearchOptions{}).SetMax(-5)} {
+		if _, err := client.Search(options); err == nil {
+			t.Errorf("Search(%+v) succeeded, want a validation error", options)
+		}
+		if _, err := client.Headlines(options); err == nil {
+			t.Errorf("Headlines(%+v) succeeded, want a validation error", options)
+		}
+	}
+	if n := len(log.all()); n != 0 {
+		t.Errorf("invalid options sent %d requests, want 0", n)
+	}
+}
+
+func TestExplicitZeroSent(t *testing.T) {
+	server, log := recordingServer(t, `{"totalArticles":0,"articles":[]}`)
+	client := newTestClient(t, server.URL, WithDefaultSearchOptions(SearchOptions{Max: 20, Page: 2}))
+
+	// The explicit zeros override the defaults instead of being dropped
+	if _, err := client.Search((&SearchOptions{}).SetMax(0).SetPage(0)); err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	query := log.all()[0].URL.Query()
+	if query.Get("max") != "0" || query.Get("page") != "0" {
+		t.Errorf("sent max=%q and page=%q, want the explicit zeros", query.Get("max"), query.Get("page"))
+	}
+}
@@ -0,0 +1,237 @@
+package allnewsapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoMorePages is returned by Pager.Next once the last page was fetched.
+var ErrNoMorePages = errors.New("no more pages")
+
+// ErrPaginationLoop is returned by Pager.Next when the API pagination does
+// not make progress: nextPage does not advance past the current page, points
+// to a page already fetched, or the pager fetched its maximum number of
+// pages. The error describes what was observed.
+var ErrPaginationLoop = errors.New("pagination loop")
+
+// defaultMaxPages is the default maximum number of pages a Pager fetches.
+const defaultMaxPages = 1000
+
+// Pager fetches consecutive pages of results.
+//
+//	pager := client.SearchPager(&allnewsapi.SearchOptions{Query: "bitcoin"})
+//	for pager.HasNext() {
+//		page, err := pager.Next(ctx)
+//		if err != nil {
+//			return err
+//		}
+//		// use page.Articles
+//	}
+//
+// A Pager is not safe for concurrent use.
+type Pager struct {
+	fetch    func(ctx context.Context, options *SearchOptions) (*SearchResponse, error)
+	endpoint string
+	next     *SearchOptions
+	last     *SearchResponse
+	newest   time.Time
+	pacing   bool
+	clock    Clock
+	defaults *SearchOptions // client defaults, encoded in cursors
+
+	// Loop guard
+	fetched  map[int]bool // pages requested or reported as current so far
+	requests int          // pages fetched so far, including repeats
+	maxPages int
+	err      error // returned by the next call to Next
+}
+
+// SearchPager returns a Pager over the search endpoint starting at the page
+// in options (or the first page).
+func (c *Client) SearchPager(options *SearchOptions) *Pager {
+	return NewSearchPager(c, options)
+}
+
+// HeadlinesPager returns a Pager over the headlines endpoint starting at the
+// page in options (or the first page).
+func (c *Client) HeadlinesPager(options *SearchOptions) *Pager {
+	return NewHeadlinesPager(c, options)
+}
+
+// NewSearchPager returns a Pager over the search results of s starting at
+// the page in options (or the first page).
+func NewSearchPager(s Searcher, options *SearchOptions) *Pager {
+	return newPager(func(ctx context.Context, options *SearchOptions) (*SearchResponse, error) {
+		return s.SearchContext(ctx, options)
+	}, searchEndpoint, options, s)
+}
+
+// NewHeadlinesPager returns a Pager over the headlines of s starting at the
+// page in options (or the first page).
+func NewHeadlinesPager(s Searcher, options *SearchOptions) *Pager {
+	return newPager(func(ctx context.Context, options *SearchOptions) (*SearchResponse, error) {
+		return s.HeadlinesContext(ctx, options)
+	}, headlinesEndpoint, options, s)
+}
+
+func newPager(fetch func(context.Context, *SearchOptions) (*SearchResponse, error), ep endpoint, options *SearchOptions, s Searcher) *Pager {
+	next := options.Clone()
+	if next == nil {
+		next = &SearchOptions{}
+	}
+	p := &Pager{
+		fetch:    fetch,
+		endpoint: ep.name,
+		next:     next,
+		clock:    realClock{},
+		fetched:  make(map[int]bool),
+		maxPages: defaultMaxPages,
+	}
+
+	// Pacing, the clock and the defaults come from the client options
+	if c, ok := s.(*Client); ok {
+		p.pacing = c.pacing
+		p.clock = c.clock
+		p.defaults = c.defaults
+	}
+	return p
+}
+
+// SetMaxPages sets the maximum number of pages the pager fetches before
+// failing with ErrPaginationLoop, 1000 by default. Values below 1 restore
+// the default. It returns p to allow chaining.
+func (p *Pager) SetMaxPages(n int) *Pager {
+	if n < 1 {
+		n = defaultMaxPages
+	}
+	p.maxPages = n
+	return p
+}
+
+// HasNext reports whether another page can be fetched, or an
+// ErrPaginationLoop is pending.
+func (p *Pager) HasNext() bool {
+	return p.next != nil || p.err != nil
+}
+
+// Next fetches the next page. It returns ErrNoMorePages once the last page
+// was fetched. When the pagination of the page just fetched does not make
+// progress, that page is returned normally and the following call returns
+// an error wrapping ErrPaginationLoop.
+func (p *Pager) Next(ctx context.Context) (*SearchResponse, error) {
+	if p.err != nil {
+		err := p.err
+		p.err = nil
+		return nil, err
+	}
+	if p.next == nil {
+		return nil, ErrNoMorePages
+	}
+
+	if p.pacing && p.last != nil {
+		if err := sleep(ctx, p.clock, p.last.RateLimit.pacingDelay(p.clock.Now())); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := p.fetch(ctx, p.next)
+	if err != nil {
+		return nil, err
+	}
+
+	p.last = resp
+	p.err = p.checkProgress(resp)
+	if p.err != nil {
+		p.next = nil
+	} else {
+		p.next = resp.NextPageOptions(p.next)
+	}
+	for _, a := range resp.Articles {
+		if a.PublishedAt.After(p.newest) {
+			p.newest = a.PublishedAt
+		}
+	}
+	return resp, nil
+}
+
+// checkProgress records the page resp was fetched for and returns an error
+// when its nextPage would make the pager loop. Both the requested page and
+// the current page reported by the server are recorded, so a server
+// reporting an earlier page than the one requested cannot send the pager
+// back to it forever.
+func (p *Pager) checkProgress(resp *SearchResponse) error {
+	requested := 1
+	if p.next.PageSet() {
+		requested = p.next.Page
+	}
+	current := resp.CurrentPage
+	if current == 0 {
+		current = requested
+	}
+	p.fetched[requested] = true
+	p.fetched[current] = true
+	p.requests++
+
+	if resp.NextPage == nil {
+		return nil
+	}
+	next := *resp.NextPage
+	switch {
+	case resp.TotalPages > 0 && next > resp.TotalPages:
+		return nil // past the last page, handled by NextPageOptions
+	case next <= current:
+		return fmt.Errorf("%w: nextPage %d does not advance past page %d", ErrPaginationLoop, next, current)
+	case p.fetched[next]:
+		return fmt.Errorf("%w: nextPage %d was already fetched", ErrPaginationLoop, next)
+	case p.requests >= p.maxPages:
+		return fmt.Errorf("%w: stopped after %d pages, nextPage is %d", ErrPaginationLoop, p.requests, next)
+	}
+	return nil
+}
+
+// Newest returns the newest PublishedAt of the articles fetched so far,
+// including those fetched before the pager was resumed from a Cursor.
+func (p *Pager) Newest() time.Time {
+	return p.newest
+}
+
+// TotalPages returns the total number of pages as reported by the last
+// fetched page, preferring the server-provided total over an estimate. It
+// returns 0 before the first page was fetched.
+func (p *Pager) TotalPages() int {
+	return p.last.TotalPagesEstimate()
+}
+
+// SearchAll fetches every page of search results and returns all articles.
+// On error, the articles fetched so far are returned along with the error.
+func (c *Client) SearchAll(ctx context.Context, options *SearchOptions) ([]Article, error) {
+	return CollectAll(ctx, c.SearchPager(options))
+}
+
+// CollectAll fetches the remaining pages of pager and returns all their
+// articles. On error, the articles fetched so far are returned along with
+// the error.
+func CollectAll(ctx context.Context, pager *Pager) ([]Article, error) {
+	var articles []Article
+	for pager.HasNext() {
+		page, err := pager.Next(ctx)
+		if err != nil {
+			return articles, err
+		}
+		articles = append(articles, page.Articles...)
+	}
+	return articles, nil
+}
+
+// WithAdaptivePacing makes Pager and SearchAll pace page requests using the
+// X-RateLimit-Remaining and X-RateLimit-Reset headers of the previous page,
+// spreading the remaining requests evenly over the time left until the rate
+// limit resets instead of exhausting them immediately. A wait never exceeds
+// the time until the reset and is interrupted when the context is done.
+func WithAdaptivePacing() ClientOption {
+	return func(c *Client) {
+		c.pacing = true
+	}
+}
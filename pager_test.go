@@ -0,0 +1,176 @@
+package allnewsapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// scriptedSearcher answers each page with the pagination returned by script
+// for the requested page, and records the pages requested. It implements
+// only the methods the pagers call.
+type scriptedSearcher struct {
+	Searcher
+	script func(page int) (current int, next *int, total int)
+	pages  []int
+}
+
+func (s *scriptedSearcher) SearchContext(ctx context.Context, options *SearchOptions, callOpts ...CallOption) (*SearchResponse, error) {
+	page := 1
+	if options.PageSet() {
+		page = options.Page
+	}
+	s.pages = append(s.pages, page)
+	current, next, total := s.script(page)
+	return &SearchResponse{
+		CurrentPage: current,
+		NextPage:    next,
+		TotalPages:  total,
+		Articles:    []Article{{Title: fmt.Sprint("page ", page)}},
+	}, nil
+}
+
+func (s *scriptedSearcher) HeadlinesContext(ctx context.Context, options *SearchOptions, callOpts ...CallOption) (*SearchResponse, error) {
+	return s.SearchContext(ctx, options, callOpts...)
+}
+
+// drain calls Next until the pager has no next page or fails, and returns
+// the error, nil when the pages ran out normally.
+func drain(p *Pager) error {
+	for p.HasNext() {
+		if _, err := p.Next(context.Background()); err != nil {
+			return err
+		}
+	}
+	if _, err := p.Next(context.Background()); !errors.Is(err, ErrNoMorePages) {
+		return fmt.Errorf("Next after the last page = %v, want ErrNoMorePages", err)
+	}
+	return nil
+}
+
+func TestPagerProgress(t *testing.T) {
+	tests := []struct {
+		name      string
+		script    func(page int) (current int, next *int, total int)
+		start     int
+		wantPages string
+		wantErr   string // empty for a normal end
+	}{
+		{"linear", func(page int) (int, *int, int) {
+			if page < 4 {
+				return page, Int(page + 1), 4
+			}
+			return page, nil, 4
+		}, 0, "[1 2 3 4]", ""},
+		{"next past total pages", func(page int) (int, *int, int) {
+			return page, Int(page + 1), 2
+		}, 0, "[1 2]", ""},
+		{"skipping pages", func(page int) (int, *int, int) {
+			if page < 7 {
+				return page, Int(page + 3), 0
+			}
+			return page, nil, 0
+		}, 0, "[1 4 7]", ""},
+		{"no current page", func(page int) (int, *int, int) {
+			if page < 3 {
+				return 0, Int(page + 1), 0
+			}
+			return 0, nil, 0
+		}, 2, "[2 3]", ""},
+
+		{"stuck", func(page int) (int, *int, int) {
+			return page, Int(page), 0
+		}, 0, "[1]", "nextPage 1 does not advance past page 1"},
+		{"stuck without current page", func(page int) (int, *int, int) {
+			return 0, Int(5), 0
+		}, 5, "[5]", "nextPage 5 does not advance past page 5"},
+		{"backwards", func(page int) (int, *int, int) {
+			if page == 3 {
+				return 3, Int(1), 0
+			}
+			return page, Int(page + 1), 0
+		}, 0, "[1 2 3]", "nextPage 1 does not advance past page 3"},
+		{"back to a fetched page", func(page int) (int, *int, int) {
+			// Page 3 claims to be page 1, then points to page 2 again
+			if page == 3 {
+				return 1, Int(2), 0
+			}
+			return page, Int(page + 1), 0
+		}, 0, "[1 2 3]", "nextPage 2 was already fetched"},
+		{"current page behind the requested one", func(page int) (int, *int, int) {
+			// Page 3 keeps claiming to be page 2 and pointing to page 3
+			if page >= 3 {
+				return 2, Int(3), 0
+			}
+			return page, Int(page + 1), 0
+		}, 0, "[1 2 3]", "nextPage 3 was already fetched"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &scriptedSearcher{script: tt.script}
+			var options *SearchOptions
+			if tt.start > 0 {
+				options = (&SearchOptions{}).SetPage(tt.start)
+			}
+			p := NewSearchPager(s, options).SetMaxPages(50)
+
+			err := drain(p)
+			if got := fmt.Sprint(s.pages); got != tt.wantPages {
+				t.Errorf("fetched pages %s, want %s", got, tt.wantPages)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("pagination ended with %v, want a normal end", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrPaginationLoop) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("pagination ended with %v, want ErrPaginationLoop: %s", err, tt.wantErr)
+			}
+			if p.HasNext() {
+				t.Error("HasNext after the loop error, want false")
+			}
+		})
+	}
+}
+
+func TestPagerLongSequence(t *testing.T) {
+	endless := func(page int) (int, *int, int) {
+		return page, Int(page + 1), 0
+	}
+
+	// The default limit stops an endless sequence after 1000 pages
+	s := &scriptedSearcher{script: endless}
+	err := drain(NewSearchPager(s, nil))
+	if !errors.Is(err, ErrPaginationLoop) || !strings.Contains(err.Error(), "stopped after 1000 pages, nextPage is 1001") {
+		t.Errorf("pagination ended with %v, want the page limit", err)
+	}
+	if len(s.pages) != 1000 || s.pages[999] != 1000 {
+		t.Errorf("fetched %d pages, want 1000", len(s.pages))
+	}
+
+	// Values below 1 restore the default
+	for _, n := range []int{0, -1} {
+		s = &scriptedSearcher{script: endless}
+		err = drain(NewSearchPager(s, nil).SetMaxPages(5).SetMaxPages(n))
+		if !errors.Is(err, ErrPaginationLoop) || len(s.pages) != 1000 {
+			t.Errorf("SetMaxPages(%d): fetched %d pages and ended with %v, want the default limit", n, len(s.pages), err)
+		}
+	}
+
+	// A higher limit lets a long finite sequence complete
+	s = &scriptedSearcher{script: func(page int) (int, *int, int) {
+		if page < 5000 {
+			return page, Int(page + 1), 5000
+		}
+		return page, nil, 5000
+	}}
+	if err := drain(NewSearchPager(s, nil).SetMaxPages(5000)); err != nil {
+		t.Errorf("pagination ended with %v, want a normal end", err)
+	}
+	if len(s.pages) != 5000 {
+		t.Errorf("fetched %d pages, want 5000", len(s.pages))
+	}
+}
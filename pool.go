@@ -0,0 +1,49 @@
+package allnewsapi
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// maxPooledBuffer is the largest buffer kept for reuse, so that one huge
+// response does not pin its memory for the life of the process.
+const maxPooledBuffer = 4 << 20
+
+// bufferPool holds the buffers response bodies are read into.
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// getBuffer returns an empty buffer from the pool.
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer returns buf to the pool. Its contents must no longer be
+// referenced.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+// readPooled reads r into a pooled buffer, growing it to sizeHint first when
+// the size is known. The buffer must be returned with putBuffer.
+func readPooled(r io.Reader, sizeHint int64) (*bytes.Buffer, error) {
+	buf := getBuffer()
+	if sizeHint > 0 && sizeHint <= maxPooledBuffer {
+		buf.Grow(int(sizeHint))
+	}
+	if _, err := buf.ReadFrom(r); err != nil {
+		putBuffer(buf)
+		return nil, err
+	}
+	return buf, nil
+}
@@ -0,0 +1,234 @@
+package allnewsapi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestReadPooledStartsEmpty(t *testing.T) {
+	big, err := readPooled(strings.NewReader(strings.Repeat("x", 1<<16)), 1<<16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	putBuffer(big)
+
+	for i := 0; i < 10; i++ {
+		buf, err := readPooled(strings.NewReader("small"), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != "small" {
+			t.Fatalf("read %q, want only the new data", got)
+		}
+		putBuffer(buf)
+	}
+}
+
+// poolBodies returns response bodies of decreasing size, so that a reused
+// buffer that was not reset would leave the tail of a larger body behind
+// a smaller one. Articles have an unknown field; with malformed, each body
+// also starts with a malformed article.
+func poolBodies(malformed bool) [][]byte {
+	var bodies [][]byte
+	for i, n := range []int{30, 10, 3, 1} {
+		body := fatResponseBody(n, 3000/(i+1))
+		body = bytes.ReplaceAll(body, []byte("Article "), []byte(fmt.Sprintf("Body %d article ", i)))
+		if malformed {
+			body = bytes.Replace(body, []byte(`"articles":[`), []byte(fmt.Sprintf(`"articles":[{"title":["bad %d"]},`, i)), 1)
+		}
+		bodies = append(bodies, body)
+	}
+	return bodies
+}
+
+// gzipHandler compresses the responses of handler with gzip.
+func gzipHandler(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rec := httptest.NewRecorder()
+		handler(rec, r)
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(rec.Body.Bytes())
+		zw.Close()
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(rec.Code)
+		w.Write(buf.Bytes())
+	}
+}
+
+func TestPooledBuffersDoNotLeakBetweenRequests(t *testing.T) {
+	errorBody := []byte(`{"message":"` + strings.Repeat("e", 100) + `"}`)
+
+	for _, mode := range []struct {
+		name string
+		opts []ClientOption
+		gzip bool
+		dec  decodeOptions
+	}{
+		{"default", nil, false, decodeOptions{}},
+		{"lenient", []ClientOption{WithLenientDecoding(), WithUnknownFields()}, false, decodeOptions{lenient: true, captureUnknown: true}},
+		{"gzip", []ClientOption{WithCompression(true)}, true, decodeOptions{}},
+		{"lenient gzip", []ClientOption{WithLenientDecoding(), WithUnknownFields(), WithCompression(true)}, true, decodeOptions{lenient: true, captureUnknown: true}},
+	} {
+		t.Run(mode.name, func(t *testing.T) {
+			bodies := poolBodies(mode.dec.lenient)
+			var handler http.HandlerFunc = func(w http.ResponseWriter, r *http.Request) {
+				i, err := strconv.Atoi(r.URL.Query().Get("q"))
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write(errorBody)
+					return
+				}
+				w.Write(bodies[i])
+			}
+			if mode.gzip {
+				handler = gzipHandler(handler)
+			}
+			client := newTestClient(t, newTestServer(t, handler).URL, mode.opts...)
+
+			check := func(t *testing.T, q string) {
+				resp, err := client.Search(&SearchOptions{Query: q})
+				i, convErr := strconv.Atoi(q)
+				if convErr != nil {
+					var apiErr *APIError
+					if !errors.As(err, &apiErr) || !bytes.Equal(apiErr.Body, errorBody) {
+						t.Errorf("Search(%s) error = %v, want the error body", q, err)
+					}
+					return
+				}
+				if err != nil {
+					t.Errorf("Search(%s): %v", q, err)
+					return
+				}
+
+				// Later requests reuse the buffer this response was read into
+				for j := range bodies {
+					client.Search(&SearchOptions{Query: strconv.Itoa(len(bodies) - 1 - j)})
+				}
+				client.Search(&SearchOptions{Query: "error"})
+
+				var want SearchResponse
+				if err := want.decode(bodies[i], mode.dec); err != nil {
+					t.Errorf("decoding body %d: %v", i, err)
+					return
+				}
+				if !reflect.DeepEqual(resp.Articles, want.Articles) {
+					t.Errorf("Search(%s) articles changed after later requests", q)
+				}
+				if !reflect.DeepEqual(resp.DecodeReport, want.DecodeReport) {
+					t.Errorf("Search(%s) report changed after later requests: %+v", q, resp.DecodeReport)
+				}
+			}
+
+			for i := range bodies {
+				check(t, strconv.Itoa(i))
+			}
+			check(t, "error")
+
+			// Concurrent requests share the pool
+			var wg sync.WaitGroup
+			for g := 0; g < 8; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					check(t, strconv.Itoa(g%len(bodies)))
+				}(g)
+			}
+			wg.Wait()
+		})
+	}
+}
+
+// BenchmarkDecodeResponse compares decoding a 100-article response with
+// large content through a raw copy of each article, as done before direct
+// decoding, and directly.
+func BenchmarkDecodeResponse(b *testing.B) {
+	body := fatResponseBody(100, 2000)
+	b.Run("intermediate", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(body)))
+		for i := 0; i < b.N; i++ {
+			var r SearchResponse
+			var aux struct {
+				pageFields
+				Articles json.RawMessage `json:"articles"`
+			}
+			if err := json.Unmarshal(body, &aux); err != nil {
+				b.Fatal(err)
+			}
+			if err := r.decodeArticles(aux.Articles, decodeOptions{}); err != nil {
+				b.Fatal(err)
+			}
+			if _, _, _, _, _, err := aux.parse(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("direct", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(body)))
+		for i := 0; i < b.N; i++ {
+			var r SearchResponse
+			if err := r.decodeDirect(body, 100); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkReadBody compares reading a 100-article response body with
+// io.ReadAll, as done before pooling, and into a pooled buffer.
+func BenchmarkReadBody(b *testing.B) {
+	body := fatResponseBody(100, 2000)
+	b.Run("readall", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(body)))
+		for i := 0; i < b.N; i++ {
+			if _, err := io.ReadAll(bytes.NewReader(body)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(body)))
+		for i := 0; i < b.N; i++ {
+			buf, err := readPooled(bytes.NewReader(body), int64(len(body)))
+			if err != nil {
+				b.Fatal(err)
+			}
+			putBuffer(buf)
+		}
+	})
+}
+
+func TestHedgeLosersReleaseBodies(t *testing.T) {
+	var released int
+	results := make(chan fetchResult, 3)
+	for _, result := range []fetchResult{
+		{resp: &response{body: []byte("late"), release: func() { released++ }}},
+		{err: errors.New("cancelled")},
+		{resp: &response{body: []byte("late too"), release: func() { released++ }}},
+	} {
+		results <- result
+	}
+
+	releaseLosers(results, 3)
+	if released != 2 {
+		t.Errorf("released %d bodies, want 2", released)
+	}
+	if len(results) != 0 {
+		t.Errorf("%d results left unreceived", len(results))
+	}
+}
@@ -0,0 +1,211 @@
+package allnewsapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrPresetNotFound is returned for a preset name that was not
+	// registered.
+	ErrPresetNotFound = errors.New("preset not found")
+
+	// ErrPresetExists is returned when registering a preset name that is
+	// already in use on a client created with WithPresetReplacement(false).
+	ErrPresetExists = errors.New("preset already registered")
+)
+
+// presets is a concurrency-safe set of named SearchOptions.
+type presets struct {
+	mu        sync.RWMutex
+	byName    map[string]*SearchOptions
+	noReplace bool
+}
+
+// WithPresetReplacement sets whether RegisterPreset may replace a preset
+// that is already registered. Replacement is allowed by default; when
+// disallowed, registering a used name fails with ErrPresetExists.
+func WithPresetReplacement(allowed bool) ClientOption {
+	return func(c *Client) {
+		c.presets.noReplace = !allowed
+	}
+}
+
+// RegisterPreset stores a copy of options under name for use with
+// SearchPreset. The options are validated first.
+func (c *Client) RegisterPreset(name string, options *SearchOptions) error {
+	return c.registerPresets(map[string]*SearchOptions{name: options})
+}
+
+// registerPresets validates and stores all presets, or none of them.
+func (c *Client) registerPresets(byName map[string]*SearchOptions) error {
+	for name, options := range byName {
+		if name == "" {
+			return errors.New("preset name is required")
+		}
+		if err := options.Validate(); err != nil {
+			return fmt.Errorf("preset %q: %w", name, err)
+		}
+	}
+
+	p := &c.presets
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.noReplace {
+		for name := range byName {
+			if _, ok := p.byName[name]; ok {
+				return fmt.Errorf("%w: %q", ErrPresetExists, name)
+			}
+		}
+	}
+	if p.byName == nil {
+		p.byName = make(map[string]*SearchOptions)
+	}
+	for name, options := range byName {
+		stored := options.Clone()
+		if stored == nil {
+			stored = &SearchOptions{}
+		}
+		p.byName[name] = stored
+	}
+	return nil
+}
+
+// Preset returns a copy of the options registered under name.
+func (c *Client) Preset(name string) (*SearchOptions, bool) {
+	c.presets.mu.RLock()
+	defer c.presets.mu.RUnlock()
+
+	options, ok := c.presets.byName[name]
+	return options.Clone(), ok
+}
+
+// ListPresets returns the names of the registered presets in sorted order.
+func (c *Client) ListPresets() []string {
+	c.presets.mu.RLock()
+	defer c.presets.mu.RUnlock()
+
+	names := make([]string, 0, len(c.presets.byName))
+	for name := range c.presets.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SearchPreset searches with the options registered under name, with
+// overrides merged over them using SearchOptions.Merge. The client defaults
+// set with WithDefaultSearchOptions still apply underneath the preset.
+func (c *Client) SearchPreset(ctx context.Context, name string, overrides *SearchOptions, callOpts ...CallOption) (*SearchResponse, error) {
+	preset, ok := c.Preset(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrPresetNotFound, name)
+	}
+	return c.SearchContext(ctx, preset.Merge(overrides), callOpts...)
+}
+
+// presetJSON is the JSON form of a preset. Dates are stored in their wire
+// format.
+type presetJSON struct {
+	Query      string   `json:"q,omitempty"`
+	StartDate  string   `json:"startDate,omitempty"`
+	EndDate    string   `json:"endDate,omitempty"`
+	Content    *bool    `json:"content,omitempty"`
+	Lang       []string `json:"lang,omitempty"`
+	Country    []string `json:"country,omitempty"`
+	Region     []string `json:"region,omitempty"`
+	Category   []string `json:"category,omitempty"`
+	Max        *int     `json:"max,omitempty"`
+	Attributes []string `json:"attributes,omitempty"`
+	Page       *int     `json:"page,omitempty"`
+	SortBy     string   `json:"sortby,omitempty"`
+	Publisher  []string `json:"publisher,omitempty"`
+	Format     string   `json:"format,omitempty"`
+}
+
+// ExportPresets encodes the registered presets as a JSON object keyed by
+// preset name, for example to store them in a configuration file. Dates set
+// as time.Time are exported in RFC 3339 format.
+func (c *Client) ExportPresets() ([]byte, error) {
+	c.presets.mu.RLock()
+	defer c.presets.mu.RUnlock()
+
+	out := make(map[string]presetJSON, len(c.presets.byName))
+	for name, o := range c.presets.byName {
+		p := presetJSON{
+			Query:      o.Query,
+			Content:    o.Content,
+			Lang:       o.Lang,
+			Country:    o.Country,
+			Region:     o.Region,
+			Category:   o.Category,
+			Attributes: o.Attributes,
+			SortBy:     o.SortBy,
+			Publisher:  o.Publisher,
+			Format:     o.Format,
+		}
+		var err error
+		if o.StartDate != nil {
+			if p.StartDate, err = formatDate(o.StartDate); err != nil {
+				return nil, fmt.Errorf("preset %q: startDate: %w", name, err)
+			}
+		}
+		if o.EndDate != nil {
+			if p.EndDate, err = formatDate(o.EndDate); err != nil {
+				return nil, fmt.Errorf("preset %q: endDate: %w", name, err)
+			}
+		}
+		if o.MaxSet() {
+			p.Max = Int(o.Max)
+		}
+		if o.PageSet() {
+			p.Page = Int(o.Page)
+		}
+		out[name] = p
+	}
+	return json.Marshal(out)
+}
+
+// ImportPresets registers the presets encoded by ExportPresets. Either all
+// presets are registered or, on error, none of them.
+func (c *Client) ImportPresets(data []byte) error {
+	var in map[string]presetJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return fmt.Errorf("error decoding presets: %w", err)
+	}
+
+	byName := make(map[string]*SearchOptions, len(in))
+	for name, p := range in {
+		o := &SearchOptions{
+			Query:      p.Query,
+			Content:    p.Content,
+			Lang:       p.Lang,
+			Country:    p.Country,
+			Region:     p.Region,
+			Category:   p.Category,
+			Attributes: p.Attributes,
+			SortBy:     p.SortBy,
+			Publisher:  p.Publisher,
+			Format:     p.Format,
+		}
+		if p.StartDate != "" {
+			o.StartDate = p.StartDate
+		}
+		if p.EndDate != "" {
+			o.EndDate = p.EndDate
+		}
+		if p.Max != nil {
+			o.SetMax(*p.Max)
+		}
+		if p.Page != nil {
+			o.SetPage(*p.Page)
+		}
+		byName[name] = o
+	}
+	return c.registerPresets(byName)
+}
@@ -0,0 +1,138 @@
+package allnewsapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+func TestSearchPresetPrecedence(t *testing.T) {
+	server, log := recordingServer(t, `{"totalArticles":0,"articles":[]}`)
+	client := newTestClient(t, server.URL, WithDefaultSearchOptions(SearchOptions{
+		Lang: []string{"en"}, Country: []string{"us"}, SortBy: "relevance", Max: 10,
+	}))
+	if err := client.RegisterPreset("tech", &SearchOptions{
+		Query: "ai", Country: []string{"gb"}, Category: []string{"technology"}, Max: 20,
+	}); err != nil {
+		t.Fatalf("RegisterPreset: %v", err)
+	}
+
+	overrides := &SearchOptions{Category: []string{}, Max: 5}
+	overrides.SetPage(3)
+	if _, err := client.SearchPreset(context.Background(), "tech", overrides); err != nil {
+		t.Fatalf("SearchPreset: %v", err)
+	}
+
+	// Overrides win over the preset, which wins over the client defaults;
+	// the empty Category override clears the preset filter
+	query := log.all()[0].URL.Query()
+	want := url.Values{
+		"apikey": {testAPIKey}, "q": {"ai"}, "lang": {"en"}, "country": {"gb"},
+		"sortby": {"relevance"}, "max": {"5"}, "page": {"3"},
+	}
+	if query.Encode() != want.Encode() {
+		t.Errorf("query = %s, want %s", query.Encode(), want.Encode())
+	}
+
+	// The stored preset is unaffected by the overrides
+	preset, _ := client.Preset("tech")
+	if preset.Max != 20 || preset.PageSet() || len(preset.Category) != 1 {
+		t.Errorf("preset = %+v, want it unchanged", preset)
+	}
+}
+
+func TestPresetErrors(t *testing.T) {
+	client := newTestClient(t, "http://127.0.0.1:1", WithPresetReplacement(false))
+	if _, err := client.SearchPreset(context.Background(), "missing", nil); !errors.Is(err, ErrPresetNotFound) {
+		t.Errorf("SearchPreset error = %v, want ErrPresetNotFound", err)
+	}
+	if err := client.RegisterPreset("", nil); err == nil {
+		t.Error("registered a preset without a name")
+	}
+	if err := client.RegisterPreset("bad", &SearchOptions{Max: 500}); err == nil {
+		t.Error("registered a preset with invalid options")
+	}
+	if err := client.RegisterPreset("a", nil); err != nil {
+		t.Fatalf("RegisterPreset: %v", err)
+	}
+	if err := client.RegisterPreset("a", nil); !errors.Is(err, ErrPresetExists) {
+		t.Errorf("RegisterPreset of a used name error = %v, want ErrPresetExists", err)
+	}
+
+	// An import with one used name registers nothing
+	if err := client.ImportPresets([]byte(`{"b":{"q":"x"},"a":{"q":"y"}}`)); !errors.Is(err, ErrPresetExists) {
+		t.Errorf("ImportPresets error = %v, want ErrPresetExists", err)
+	}
+	if names := fmt.Sprint(client.ListPresets()); names != "[a]" {
+		t.Errorf("presets = %s, want [a]", names)
+	}
+}
+
+func TestPresetsConcurrent(t *testing.T) {
+	server, _ := recordingServer(t, `{"totalArticles":0,"articles":[]}`)
+	client := newTestClient(t, server.URL)
+	if err := client.RegisterPreset("shared", &SearchOptions{Query: "q", Lang: []string{"en"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("p%d", i)
+			for j := 0; j < 20; j++ {
+				if err := client.RegisterPreset(name, &SearchOptions{Query: name, Max: j + 1}); err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := client.SearchPreset(context.Background(), "shared", &SearchOptions{Max: j + 1}); err != nil {
+					t.Error(err)
+					return
+				}
+				// Copies returned by Preset can be modified freely
+				if preset, ok := client.Preset("shared"); ok {
+					preset.Lang[0] = name
+				}
+				client.ListPresets()
+				if _, err := client.ExportPresets(); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if n := len(client.ListPresets()); n != 9 {
+		t.Errorf("registered %d presets, want 9", n)
+	}
+	if preset, _ := client.Preset("shared"); preset.Lang[0] != "en" {
+		t.Errorf("shared preset Lang = %v, want it unchanged", preset.Lang)
+	}
+}
+
+func TestPresetsExportImport(t *testing.T) {
+	source := newTestClient(t, "http://127.0.0.1:1")
+	options := (&SearchOptions{Query: "q", StartDate: testTime, Content: Bool(false), Lang: []string{"en"}}).SetMax(0)
+	if err := source.RegisterPreset("p", options); err != nil {
+		t.Fatalf("RegisterPreset: %v", err)
+	}
+	data, err := source.ExportPresets()
+	if err != nil {
+		t.Fatalf("ExportPresets: %v", err)
+	}
+
+	target := newTestClient(t, "http://127.0.0.1:1")
+	if err := target.ImportPresets(data); err != nil {
+		t.Fatalf("ImportPresets: %v", err)
+	}
+	imported, _ := target.Preset("p")
+	want, _ := canonicalOptions(nil, options)
+	if got, _ := canonicalOptions(nil, imported); got != want {
+		t.Errorf("imported preset encodes as %s, want %s", got, want)
+	}
+}
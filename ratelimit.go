@@ -0,0 +1,72 @@
+package allnewsapi
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit holds the rate limit state reported by the API in the
+// X-RateLimit-* response headers.
+type RateLimit struct {
+	Limit     int       // Requests allowed per window, -1 if not reported
+	Remaining int       // Requests left in the current window, -1 if not reported
+	Reset     time.Time // When the window resets, zero if not reported
+}
+
+// parseRateLimit reads the rate limit headers of a response, returning nil
+// when none are present. X-RateLimit-Reset is accepted both as a Unix
+// timestamp and as a number of seconds from now.
+func parseRateLimit(header http.Header, now time.Time) *RateLimit {
+	limit, hasLimit := headerInt(header, "X-RateLimit-Limit")
+	remaining, hasRemaining := headerInt(header, "X-RateLimit-Remaining")
+	reset, hasReset := headerInt(header, "X-RateLimit-Reset")
+	if !hasLimit && !hasRemaining && !hasReset {
+		return nil
+	}
+
+	rl := &RateLimit{Limit: -1, Remaining: -1}
+	if hasLimit {
+		rl.Limit = limit
+	}
+	if hasRemaining {
+		rl.Remaining = remaining
+	}
+	if hasReset {
+		// Values this large can only be timestamps
+		if reset > 1e9 {
+			rl.Reset = time.Unix(int64(reset), 0)
+		} else {
+			rl.Reset = now.Add(time.Duration(reset) * time.Second)
+		}
+	}
+	return rl
+}
+
+// headerInt parses an integer header value.
+func headerInt(header http.Header, key string) (int, bool) {
+	value := header.Get(key)
+	if value == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(value)
+	return n, err == nil
+}
+
+// pacingDelay returns how long to wait before the next request so that the
+// remaining requests are spread evenly until the rate limit window resets.
+// The delay never exceeds the time left until the reset.
+func (rl *RateLimit) pacingDelay(now time.Time) time.Duration {
+	if rl == nil || rl.Remaining < 0 || rl.Reset.IsZero() {
+		return 0
+	}
+
+	untilReset := rl.Reset.Sub(now)
+	if untilReset <= 0 {
+		return 0
+	}
+	if rl.Remaining == 0 {
+		return untilReset
+	}
+	return untilReset / time.Duration(rl.Remaining)
+}
@@ -0,0 +1,182 @@
+package allnewsapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	allnewsapi "github.com/AllNewsAPI/go-sdk"
+	"github.com/AllNewsAPI/go-sdk/allnewsapitest"
+)
+
+// rateLimitedServer serves pages of one article with rate limit headers.
+// Remaining starts at remaining and decrements with every request; the
+// window resets at reset. It records when each request arrived on clock.
+type rateLimitedServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	remaining int
+	arrivals  []time.Duration // since start
+}
+
+func newRateLimitedServer(t *testing.T, clock *allnewsapitest.FakeClock, pages, remaining int, reset time.Time) *rateLimitedServer {
+	s := &rateLimitedServer{remaining: remaining}
+	start := clock.Now()
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.arrivals = append(s.arrivals, clock.Now().Sub(start))
+		s.remaining--
+		remaining := s.remaining
+		s.mu.Unlock()
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		next := "null"
+		if page < pages {
+			next = strconv.Itoa(page + 1)
+		}
+
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		fmt.Fprintf(w, `{"totalArticles":%d,"currentPage":%d,"nextPage":%s,"articles":[{"title":"page %d"}]}`,
+			pages, page, next, page)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *rateLimitedServer) arrivalTimes() []time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]time.Duration(nil), s.arrivals...)
+}
+
+// collectPaced fetches every page of pager. Before page i it expects the
+// pager to wait for waits[i] on clock, and advances the clock by that much
+// once the pager is waiting.
+func collectPaced(t *testing.T, pager *allnewsapi.Pager, clock *allnewsapitest.FakeClock, waits ...time.Duration) []allnewsapi.Article {
+	t.Helper()
+	type result struct {
+		resp *allnewsapi.SearchResponse
+		err  error
+	}
+	var articles []allnewsapi.Article
+	for i := 0; pager.HasNext(); i++ {
+		if i >= len(waits) {
+			t.Fatalf("pager has more than the %d expected pages", len(waits))
+		}
+		done := make(chan result, 1)
+		go func() {
+			resp, err := pager.Next(context.Background())
+			done <- result{resp, err}
+		}()
+		if waits[i] > 0 {
+			clock.BlockUntil(1)
+			clock.Advance(waits[i])
+		}
+		r := <-done
+		if r.err != nil {
+			t.Fatalf("page %d: %v", i+1, r.err)
+		}
+		articles = append(articles, r.resp.Articles...)
+	}
+	return articles
+}
+
+func TestAdaptivePacingSpreadsRequests(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := allnewsapitest.NewFakeClock(start)
+
+	// After the first request 6 requests remain for 60s, so requests are
+	// spaced 10s apart: (60s - elapsed) / remaining stays at 10s
+	server := newRateLimitedServer(t, clock, 4, 7, start.Add(time.Minute))
+	client := newClient(t, server.URL, allnewsapi.WithClock(clock), allnewsapi.WithAdaptivePacing())
+
+	articles := collectPaced(t, client.SearchPager(nil), clock, 0, 10*time.Second, 10*time.Second, 10*time.Second)
+	if len(articles) != 4 {
+		t.Fatalf("got %d articles, want 4", len(articles))
+	}
+	want := []time.Duration{0, 10 * time.Second, 20 * time.Second, 30 * time.Second}
+	if got := server.arrivalTimes(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("requests arrived at %v, want %v", got, want)
+	}
+}
+
+func TestAdaptivePacingWaitsForResetWhenExhausted(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := allnewsapitest.NewFakeClock(start)
+
+	// No request remains after the first one, so the second waits for the
+	// reset; the reset then lies in the past and the third is not delayed
+	server := newRateLimitedServer(t, clock, 3, 1, start.Add(45*time.Second))
+	client := newClient(t, server.URL, allnewsapi.WithClock(clock), allnewsapi.WithAdaptivePacing())
+
+	collectPaced(t, client.SearchPager(nil), clock, 0, 45*time.Second, 0)
+	want := []time.Duration{0, 45 * time.Second, 45 * time.Second}
+	if got := server.arrivalTimes(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("requests arrived at %v, want %v", got, want)
+	}
+}
+
+func TestNoPacingByDefault(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := allnewsapitest.NewFakeClock(start)
+	server := newRateLimitedServer(t, clock, 3, 3, start.Add(time.Hour))
+	client := newClient(t, server.URL, allnewsapi.WithClock(clock))
+
+	collectPaced(t, client.SearchPager(nil), clock, 0, 0, 0)
+	want := []time.Duration{0, 0, 0}
+	if got := server.arrivalTimes(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("requests arrived at %v, want %v", got, want)
+	}
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   *allnewsapi.RateLimit
+	}{
+		{"none", nil, nil},
+		{"all", map[string]string{"X-RateLimit-Limit": "100", "X-RateLimit-Remaining": "42", "X-RateLimit-Reset": strconv.FormatInt(now.Add(time.Hour).Unix(), 10)},
+			&allnewsapi.RateLimit{Limit: 100, Remaining: 42, Reset: now.Add(time.Hour)}},
+		{"relative reset", map[string]string{"X-RateLimit-Reset": "30"},
+			&allnewsapi.RateLimit{Limit: -1, Remaining: -1, Reset: now.Add(30 * time.Second)}},
+		{"remaining only", map[string]string{"X-RateLimit-Remaining": "0"},
+			&allnewsapi.RateLimit{Limit: -1, Remaining: 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for name, value := range tt.header {
+					w.Header().Set(name, value)
+				}
+				w.Write([]byte(okBody))
+			}))
+			defer server.Close()
+
+			client := newClient(t, server.URL, allnewsapi.WithClock(allnewsapitest.NewFakeClock(now)))
+			resp, err := client.Search(nil)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			got := resp.RateLimit
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("RateLimit = %+v, want %+v", got, tt.want)
+			}
+			if got != nil && (got.Limit != tt.want.Limit || got.Remaining != tt.want.Remaining || !got.Reset.Equal(tt.want.Reset)) {
+				t.Errorf("RateLimit = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package allnewsapi
+
+import (
+	"net/url"
+	"strings"
+)
+
+// redacted replaces API keys in URLs, errors and logs.
+const redacted = "REDACTED"
+
+// redactURL returns u as a string with the apikey parameter and any other
+// occurrence of apiKey in the path or query values replaced.
+func redactURL(u *url.URL, apiKey string) string {
+	if u == nil {
+		return ""
+	}
+
+	clean := *u
+	clean.User = nil
+	if apiKey != "" {
+		clean.Path = strings.ReplaceAll(clean.Path, apiKey, redacted)
+		clean.RawPath = strings.ReplaceAll(clean.RawPath, apiKey, redacted)
+	}
+
+	if clean.RawQuery != "" {
+		query := clean.Query()
+		for name, values := range query {
+			for i, value := range values {
+				if name == "apikey" {
+					values[i] = redacted
+				} else if apiKey != "" {
+					values[i] = strings.ReplaceAll(value, apiKey, redacted)
+				}
+			}
+		}
+		clean.RawQuery = query.Encode()
+	}
+
+	return clean.String()
+}
@@ -0,0 +1,93 @@
+package allnewsapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// RedirectPolicy controls how the client handles HTTP redirects.
+type RedirectPolicy int
+
+const (
+	// RedirectFollow follows redirects to any host. This is the default.
+	RedirectFollow RedirectPolicy = iota
+	// RedirectRefuse does not follow redirects; requests fail with a
+	// *RedirectError.
+	RedirectRefuse
+	// RedirectSameHost follows redirects, but removes the API key and
+	// authentication headers when a redirect leads to a different host.
+	RedirectSameHost
+)
+
+// maxRedirects matches the limit of the default http.Client.
+const maxRedirects = 10
+
+// ErrRedirect is matched by errors.Is for errors caused by a refused
+// redirect.
+var ErrRedirect = errors.New("redirect refused")
+
+// RedirectError is returned when the API responds with a redirect and the
+// client uses RedirectRefuse.
+type RedirectError struct {
+	StatusCode int    // Status code of the redirect response
+	Location   string // Redirect target with the API key redacted
+}
+
+func (e *RedirectError) Error() string {
+	return fmt.Sprintf("%v: status %d to %s", ErrRedirect, e.StatusCode, e.Location)
+}
+
+func (e *RedirectError) Unwrap() error {
+	return ErrRedirect
+}
+
+// WithRedirectPolicy sets how redirects are handled. It applies to every
+// request made by the client.
+func WithRedirectPolicy(p RedirectPolicy) ClientOption {
+	return func(c *Client) {
+		c.redirectPolicy = p
+	}
+}
+
+// checkRedirect returns the http.Client CheckRedirect function implementing
+// the client's redirect policy, or nil for the default behavior.
+func (c *Client) checkRedirect() func(req *http.Request, via []*http.Request) error {
+	switch c.redirectPolicy {
+	case RedirectRefuse:
+		return func(req *http.Request, via []*http.Request) error {
+			// The first request carries the key of the call, which may
+			// be a per-call key rather than the client's
+			apiKey := via[0].URL.Query().Get("apikey")
+			redirectErr := &RedirectError{Location: redactURL(req.URL, apiKey)}
+			if req.Response != nil {
+				redirectErr.StatusCode = req.Response.StatusCode
+			}
+			return redirectErr
+		}
+	case RedirectSameHost:
+		return func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			if !strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+				stripCredentials(req)
+			}
+			return nil
+		}
+	default:
+		return nil
+	}
+}
+
+// stripCredentials removes the API key and authentication headers from req.
+func stripCredentials(req *http.Request) {
+	query := req.URL.Query()
+	if query.Has("apikey") {
+		query.Del("apikey")
+		req.URL.RawQuery = query.Encode()
+	}
+	req.Header.Del("Authorization")
+	req.Header.Del("X-Api-Key")
+}
@@ -0,0 +1,114 @@
+package allnewsapi
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedirectPolicies(t *testing.T) {
+	const body = `{"totalArticles":1,"articles":[{"title":"moved"}]}`
+
+	for _, status := range []int{http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect} {
+		for _, tt := range []struct {
+			name      string
+			policy    RedirectPolicy
+			crossHost bool
+			wantErr   bool
+			wantKey   bool // the redirect target receives the API key
+		}{
+			{"follow same host", RedirectFollow, false, false, true},
+			{"follow cross host", RedirectFollow, true, false, true},
+			{"refuse same host", RedirectRefuse, false, true, false},
+			{"refuse cross host", RedirectRefuse, true, true, false},
+			{"same host policy, same host", RedirectSameHost, false, false, true},
+			{"same host policy, cross host", RedirectSameHost, true, false, false},
+		} {
+			t.Run(http.StatusText(status)+"/"+tt.name, func(t *testing.T) {
+				target, targetLog := recordingServer(t, body)
+				originLog := &requestLog{}
+				origin := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path != "/v1/search" {
+						originLog.add(r)
+						w.Write([]byte(body))
+						return
+					}
+					location := "/moved/search?" + r.URL.RawQuery
+					if tt.crossHost {
+						location = target.URL + location
+					}
+					http.Redirect(w, r, location, status)
+				})
+
+				client := newTestClient(t, origin.URL, WithRedirectPolicy(tt.policy))
+				resp, err := client.Search(&SearchOptions{Query: "q"})
+
+				received := originLog.all()
+				if tt.crossHost {
+					received = targetLog.all()
+				}
+
+				if tt.wantErr {
+					var redirectErr *RedirectError
+					if !errors.As(err, &redirectErr) || !errors.Is(err, ErrRedirect) {
+						t.Fatalf("Search error = %v, want a RedirectError", err)
+					}
+					if redirectErr.StatusCode != status {
+						t.Errorf("StatusCode = %d, want %d", redirectErr.StatusCode, status)
+					}
+					if strings.Contains(err.Error(), testAPIKey) || !strings.Contains(redirectErr.Location, "/moved/search") {
+						t.Errorf("Location = %q, want the target with the key redacted", redirectErr.Location)
+					}
+					if len(received) != 0 {
+						t.Errorf("redirect target received %d requests, want 0", len(received))
+					}
+					return
+				}
+
+				if err != nil {
+					t.Fatalf("Search: %v", err)
+				}
+				if len(resp.Articles) != 1 || len(received) != 1 {
+					t.Fatalf("got %d articles and %d requests at the target, want 1 and 1", len(resp.Articles), len(received))
+				}
+				query := received[0].URL.Query()
+				if got := query.Get("apikey") == testAPIKey; got != tt.wantKey {
+					t.Errorf("target received apikey %q, want key sent: %v", query.Get("apikey"), tt.wantKey)
+				}
+				if query.Get("q") != "q" {
+					t.Errorf("target received q %q, want the other parameters kept", query.Get("q"))
+				}
+			})
+		}
+	}
+}
+
+func TestRedirectSameHostLimit(t *testing.T) {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.RequestURI(), http.StatusFound)
+	})
+	_, err := newTestClient(t, server.URL, WithRedirectPolicy(RedirectSameHost)).Search(nil)
+	if err == nil || !strings.Contains(err.Error(), "stopped after 10 redirects") {
+		t.Errorf("Search error = %v, want the redirect limit error", err)
+	}
+}
+
+func TestRedirectRefuseRedactsCallAPIKey(t *testing.T) {
+	const key = "call-key"
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		// The target repeats the key outside the apikey parameter
+		key := r.URL.Query().Get("apikey")
+		http.Redirect(w, r, "/moved/"+key+"/search?token="+key+"&"+r.URL.RawQuery, http.StatusFound)
+	})
+	client := newTestClient(t, server.URL, WithRedirectPolicy(RedirectRefuse))
+
+	_, err := client.Search(&SearchOptions{Query: "q"}, WithCallAPIKey(key))
+	var redirectErr *RedirectError
+	if !errors.As(err, &redirectErr) {
+		t.Fatalf("Search error = %v, want a RedirectError", err)
+	}
+	if strings.Contains(err.Error(), key) || !strings.Contains(redirectErr.Location, "/moved/"+redacted+"/search") {
+		t.Errorf("Location = %q, want the call key redacted", redirectErr.Location)
+	}
+}
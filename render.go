@@ -0,0 +1,232 @@
+package allnewsapi
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// String returns a single-line "title — source — date" summary of the
+// article, omitting parts that are empty.
+func (a Article) String() string {
+	var parts []string
+	if title := collapseSpace(a.Title); title != "" {
+		parts = append(parts, title)
+	}
+	if a.Source.Name != "" {
+		parts = append(parts, a.Source.Name)
+	}
+	if !a.PublishedAt.IsZero() {
+		parts = append(parts, a.PublishedAt.Format(time.RFC3339))
+	} else if a.PublishedAtRaw != "" {
+		parts = append(parts, a.PublishedAtRaw)
+	}
+	return strings.Join(parts, " — ")
+}
+
+// String summarizes the totals and pages of the response.
+func (r *SearchResponse) String() string {
+	if r == nil {
+		return "<nil>"
+	}
+	s := fmt.Sprintf("%d articles", r.TotalArticles)
+	if r.CurrentPage > 0 {
+		s += fmt.Sprintf(", page %d", r.CurrentPage)
+		if pages := r.TotalPagesEstimate(); pages > 0 {
+			s += fmt.Sprintf(" of %d", pages)
+		}
+	}
+	return s + fmt.Sprintf(", %d on this page", len(r.Articles))
+}
+
+// Column is a column of the table written by RenderTable.
+type Column struct {
+	Header string
+	Width  int // Maximum width in terminal cells, 0 for no maximum
+	Value  func(Article) string
+}
+
+// TitleColumn returns a column with the article title.
+func TitleColumn() Column {
+	return Column{Header: "TITLE", Value: func(a Article) string { return a.Title }}
+}
+
+// SourceColumn returns a column with the source name.
+func SourceColumn() Column {
+	return Column{Header: "SOURCE", Width: 24, Value: func(a Article) string { return a.Source.Name }}
+}
+
+// PublishedAtColumn returns a column with the publication time in loc, or in
+// UTC when loc is nil.
+func PublishedAtColumn(loc *time.Location) Column {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return Column{Header: "PUBLISHED", Width: 22, Value: func(a Article) string {
+		if a.PublishedAt.IsZero() {
+			return a.PublishedAtRaw
+		}
+		return a.PublishedAt.In(loc).Format("2006-01-02 15:04 MST")
+	}}
+}
+
+// URLColumn returns a column with the article URL.
+func URLColumn() Column {
+	return Column{Header: "URL", Value: func(a Article) string { return a.URL }}
+}
+
+// columnGap separates table columns.
+const columnGap = "  "
+
+// minColumnWidth is the narrowest a column is shrunk to fit maxWidth.
+const minColumnWidth = 4
+
+// RenderTable writes articles as a table with aligned columns. When cols is
+// empty, the published time in UTC, the source and the title are shown.
+// Cell values are collapsed to a single line and truncated on rune
+// boundaries to fit the column widths, taking double-width (e.g. CJK)
+// characters into account. When maxWidth is positive, the widest columns are
+// narrowed until the table fits in maxWidth cells.
+func RenderTable(w io.Writer, articles []Article, cols []Column, maxWidth int) error {
+	if len(cols) == 0 {
+		cols = []Column{PublishedAtColumn(nil), SourceColumn(), TitleColumn()}
+	}
+
+	rows := make([][]string, 0, len(articles)+1)
+	header := make([]string, len(cols))
+	for i, col := range cols {
+		header[i] = col.Header
+	}
+	rows = append(rows, header)
+	for _, a := range articles {
+		row := make([]string, len(cols))
+		for i, col := range cols {
+			row[i] = collapseSpace(col.Value(a))
+		}
+		rows = append(rows, row)
+	}
+
+	widths := columnWidths(rows, cols, maxWidth)
+
+	var b strings.Builder
+	for _, row := range rows {
+		b.Reset()
+		for i, cell := range row {
+			cell = truncateWidth(cell, widths[i])
+			b.WriteString(cell)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-stringWidth(cell)))
+				b.WriteString(columnGap)
+			}
+		}
+		b.WriteByte('\n')
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// columnWidths returns the width of each column: the widest cell capped by
+// the column Width, then narrowed to fit maxWidth.
+func columnWidths(rows [][]string, cols []Column, maxWidth int) []int {
+	widths := make([]int, len(cols))
+	for _, row := range rows {
+		for i, cell := range row {
+			if w := stringWidth(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	for i, col := range cols {
+		if col.Width > 0 && widths[i] > col.Width {
+			widths[i] = col.Width
+		}
+	}
+
+	if maxWidth <= 0 {
+		return widths
+	}
+	total := len(columnGap) * (len(cols) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	for total > maxWidth {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			break
+		}
+		widths[widest]--
+		total--
+	}
+	return widths
+}
+
+// collapseSpace replaces runs of whitespace, including newlines, with a
+// single space.
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// truncateWidth shortens s to at most width terminal cells, marking the cut
+// with an ellipsis.
+func truncateWidth(s string, width int) string {
+	if stringWidth(s) <= width {
+		return s
+	}
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		rw := runeWidth(r)
+		if used+rw > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		used += rw
+	}
+	b.WriteString("…")
+	return b.String()
+}
+
+// stringWidth returns the number of terminal cells s occupies.
+func stringWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// runeWidth returns the number of terminal cells r occupies: 0 for
+// combining and format characters, 2 for East Asian wide characters and
+// emoji, 1 otherwise.
+func runeWidth(r rune) int {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case r >= 0x1100 && r <= 0x115F, // Hangul Jamo
+		r >= 0x2E80 && r <= 0x303E, // CJK radicals, punctuation
+		r >= 0x3041 && r <= 0x33FF, // Kana, CJK compatibility
+		r >= 0x3400 && r <= 0x4DBF, // CJK extension A
+		r >= 0x4E00 && r <= 0x9FFF, // CJK unified ideographs
+		r >= 0xA000 && r <= 0xA4CF, // Yi
+		r >= 0xAC00 && r <= 0xD7A3, // Hangul syllables
+		r >= 0xF900 && r <= 0xFAFF, // CJK compatibility ideographs
+		r >= 0xFE30 && r <= 0xFE4F, // CJK compatibility forms
+		r >= 0xFF00 && r <= 0xFF60, // Fullwidth forms
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x1F300 && r <= 0x1F64F, // Emoji
+		r >= 0x1F900 && r <= 0x1F9FF,
+		r >= 0x20000 && r <= 0x3FFFD: // CJK extensions B and later
+		return 2
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,109 @@
+package allnewsapi
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// checkGolden compares got with testdata/name, or rewrites the file with
+// -update.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s:\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// renderArticles covers ASCII, CJK, Hangul, combining marks, emoji and
+// whitespace that must be collapsed.
+func renderArticles() []Article {
+	articles := testArticles(6)
+	articles[0].Title = "Central bank holds rates steady as inflation cools"
+	articles[1].Title = "東京株式市場、日経平均が史上最高値を更新 半導体株が牽引"
+	articles[1].Source.Name = "日本経済新聞"
+	articles[2].Title = "서울 아파트 가격 상승세 지속"
+	articles[2].Source.Name = "연합뉴스"
+	articles[3].Title = "Cafe\u0301 culture returns to Montre\u0301al 🎉 after the winter"
+	articles[4].Title = "Line one\nline two\t\ttabbed   spaced"
+	articles[5].Title = "Short"
+	articles[5].PublishedAt = time.Time{}
+	articles[5].PublishedAtRaw = "yesterday"
+	return articles
+}
+
+func TestRenderTableGolden(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	tests := []struct {
+		name     string
+		cols     []Column
+		maxWidth int
+	}{
+		{"table_default.golden", nil, 0},
+		{"table_width_60.golden", nil, 60},
+		{"table_width_45.golden", nil, 45},
+		{"table_columns.golden", []Column{PublishedAtColumn(tokyo), {Header: "TITLE", Width: 15, Value: func(a Article) string { return a.Title }}, URLColumn()}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := RenderTable(&buf, renderArticles(), tt.cols, tt.maxWidth); err != nil {
+				t.Fatalf("RenderTable: %v", err)
+			}
+			if !utf8.Valid(buf.Bytes()) {
+				t.Fatal("output is not valid UTF-8")
+			}
+			if tt.maxWidth > 0 {
+				for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+					if w := stringWidth(line); w > tt.maxWidth {
+						t.Errorf("line %q is %d cells wide, want at most %d", line, w, tt.maxWidth)
+					}
+				}
+			}
+			checkGolden(t, tt.name, buf.Bytes())
+		})
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"hello", 5, "hello"},
+		{"hello world", 8, "hello w…"},
+		{"日本語テキスト", 14, "日本語テキスト"},
+		{"日本語テキスト", 8, "日本語…"},
+		{"日本語テキスト", 7, "日本語…"}, // the next wide rune does not fit in the remaining cell
+		{"e\u0301e\u0301e\u0301e\u0301", 3, "e\u0301e\u0301…"},
+		{"🎉🎉🎉", 4, "🎉…"},
+		{"abc", 1, "…"},
+	}
+	for _, tt := range tests {
+		got := truncateWidth(tt.s, tt.width)
+		if got != tt.want {
+			t.Errorf("truncateWidth(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+		if w := stringWidth(got); w > tt.width {
+			t.Errorf("truncateWidth(%q, %d) is %d cells wide", tt.s, tt.width, w)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+package allnewsapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// endpoint describes an API endpoint that accepts SearchOptions.
+type endpoint struct {
+	name string
+	path string
+}
+
+// Registered endpoints. A new endpoint sharing the search parameters only
+// needs an entry here and a thin exported method calling c.query.
+var (
+	searchEndpoint    = endpoint{name: "search", path: "/v1/search"}
+	headlinesEndpoint = endpoint{name: "headlines", path: "/v1/headlines"}
+)
+
+// query runs a SearchOptions based request against the given endpoint.
+func (c *Client) query(ctx context.Context, ep endpoint, options *SearchOptions, callOpts []CallOption) (*SearchResponse, error) {
+	params, err := c.params(options)
+	if err != nil {
+		return nil, err
+	}
+	return c.queryParams(ctx, ep, params, callOpts)
+}
+
+// queryParams runs a request with the given query parameters against the
+// given endpoint.
+func (c *Client) queryParams(ctx context.Context, ep endpoint, params url.Values, callOpts []CallOption) (*SearchResponse, error) {
+	cfg := newCallConfig(callOpts)
+
+	opts := c.decodeOptions()
+	opts.sizeHint, _ = strconv.Atoi(params.Get("max"))
+
+	var searchResponse SearchResponse
+	into := &optionsResponse{SearchResponse: &searchResponse, opts: opts}
+	resp, err := c.do(ctx, ep, params, into, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	searchResponse.RateLimit = parseRateLimit(resp.header, c.clock.Now())
+	searchResponse.RequestID = resp.requestID
+	searchResponse.Meta = resp.meta()
+	return &searchResponse, nil
+}
+
+// params merges options over the client defaults, validates them and
+// encodes them into query parameters.
+func (c *Client) params(options *SearchOptions) (url.Values, error) {
+	options = c.defaults.Merge(options)
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	return buildParams(options)
+}
+
+// decodeOptions returns the decode options selected by the client options.
+func (c *Client) decodeOptions() decodeOptions {
+	return decodeOptions{
+		lenient:        c.lenient,
+		strict:         c.strict,
+		captureUnknown: c.captureUnknown,
+	}
+}
+
+// buildParams encodes options into query parameters. The API key is added
+// by do.
+func buildParams(options *SearchOptions) (url.Values, error) {
+	params := url.Values{}
+	if options == nil {
+		return params, nil
+	}
+
+	if options.Query != "" {
+		params.Add("q", options.Query)
+	}
+
+	// Handle dates
+	if options.StartDate != nil {
+		startDate, err := formatDate(options.StartDate)
+		if err != nil {
+			return nil, errors.New("startDate must be string or time.Time")
+		}
+		params.Add("startDate", startDate)
+	}
+	if options.EndDate != nil {
+		endDate, err := formatDate(options.EndDate)
+		if err != nil {
+			return nil, errors.New("endDate must be string or time.Time")
+		}
+		params.Add("endDate", endDate)
+	}
+
+	// Handle boolean content parameter
+	if options.Content != nil {
+		if *options.Content {
+			params.Add("content", "true")
+		} else {
+			params.Add("content", "false")
+		}
+	}
+
+	// Handle array parameters
+	// Language tags are sent as ISO 639-1 codes
+	lang, err := normalizeLangs(options.Lang)
+	if err != nil {
+		return nil, err
+	}
+	addList(params, "lang", lang)
+	addList(params, "country", options.Country)
+	addList(params, "region", options.Region)
+	addList(params, "category", options.Category)
+	addList(params, "attributes", options.Attributes)
+	addList(params, "publisher", options.Publisher)
+
+	// Handle integer parameters
+	if options.Max > 0 || options.maxSet {
+		params.Add("max", fmt.Sprintf("%d", options.Max))
+	}
+	if options.Page > 0 || options.pageSet {
+		params.Add("page", fmt.Sprintf("%d", options.Page))
+	}
+
+	// Handle other string parameters
+	if options.SortBy != "" {
+		params.Add("sortby", options.SortBy)
+	}
+	if options.Format != "" {
+		params.Add("format", options.Format)
+	}
+
+	return params, nil
+}
+
+// addList adds a comma separated parameter when values is not empty.
+func addList(params url.Values, key string, values []string) {
+	if len(values) > 0 {
+		params.Add(key, strings.Join(values, ","))
+	}
+}
+
+// formatDate converts a string or time.Time date into its wire format.
+func formatDate(v interface{}) (string, error) {
+	switch d := v.(type) {
+	case string:
+		return d, nil
+	case time.Time:
+		return d.Format(time.RFC3339), nil
+	default:
+		return "", fmt.Errorf("unsupported date type %T", v)
+	}
+}
+
+// do performs a GET request against the endpoint, decodes the JSON response
+// into into, and returns the response it was decoded from. All endpoints go
+// through here, so cross-cutting behavior belongs in this function.
+func (c *Client) do(ctx context.Context, ep endpoint, params url.Values, into interface{}, cfg *callConfig) (*response, error) {
+	if atomic.LoadInt32(&c.closed) == 1 {
+		return nil, ErrClientClosed
+	}
+
+	apiKey := c.apiKey
+	if cfg.apiKey != "" {
+		apiKey = cfg.apiKey
+	}
+	reqURL := c.requestURL(ep, params, apiKey)
+
+	requestID := cfg.requestID
+	if requestID == "" {
+		requestID = newRequestID()
+	}
+
+	if cfg.dryRun {
+		u, err := url.Parse(reqURL)
+		if err != nil {
+			return nil, fmt.Errorf("error creating request: %w", err)
+		}
+		return &response{requestID: requestID, url: u, apiKey: apiKey}, nil
+	}
+
+	parent := ctx
+	ctx, cancel := withCallTimeout(ctx, cfg)
+	defer cancel()
+
+	metrics := RequestMetrics{Endpoint: ep.name, RequestID: requestID}
+	start := c.clock.Now()
+	finish := func(resp *response, err error) (*response, error) {
+		elapsed := c.clock.Now().Sub(start)
+		if err != nil {
+			err = callTimeoutError(err, parent, ctx, cfg)
+			err = withRequestID(retryError(err, metrics.Attempts, elapsed), requestID)
+		}
+		if c.metrics != nil {
+			metrics.Duration = elapsed
+			metrics.Err = err
+			c.metrics(metrics)
+		}
+		return resp, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		metrics.Attempts = attempt
+		resp, hedges, err := c.fetchHedged(ctx, reqURL, requestID, apiKey)
+		metrics.Hedges += hedges
+		if err == nil {
+			metrics.StatusCode = resp.statusCode
+			metrics.CompressedBytes = resp.compressedSize
+			metrics.UncompressedBytes = resp.bodySize
+			err = c.decode(resp, into)
+			resp.releaseBody()
+		}
+		if err == nil || attempt >= c.maxAttempts || !IsRetryable(err) {
+			return finish(resp, err)
+		}
+
+		var raw *http.Response
+		if resp != nil {
+			raw = resp.raw
+		}
+		delay, ok := c.backoff.NextDelay(attempt, err, raw)
+		if !ok {
+			return finish(nil, err)
+		}
+		if c.maxElapsed > 0 && c.clock.Now().Sub(start)+delay > c.maxElapsed {
+			return finish(nil, err)
+		}
+		// Context deadlines are set on the real clock
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return finish(nil, fmt.Errorf("%w before next retry (last error: %v)", context.DeadlineExceeded, err))
+		}
+		if sleepErr := sleep(ctx, c.clock, delay); sleepErr != nil {
+			return finish(nil, fmt.Errorf("%w while waiting to retry (last error: %v)", sleepErr, err))
+		}
+	}
+}
+
+// requestURL returns the URL of a request to the endpoint. params is
+// modified to carry apiKey.
+func (c *Client) requestURL(ep endpoint, params url.Values, apiKey string) string {
+	params.Set("apikey", apiKey)
+	return fmt.Sprintf("%s%s?%s", c.baseURL, ep.path, params.Encode())
+}
+
+// response is a fully read HTTP response.
+type response struct {
+	raw        *http.Response // body already consumed
+	requestID  string         // ID echoed by the server, or the one sent
+	statusCode int
+	header     http.Header // cloned from raw before the body is closed
+	url        *url.URL
+	body       []byte // pooled, nil once released
+	bodySize   int64
+	duration   time.Duration
+	release    func() // returns body to the pool
+	apiKey     string // key the request was sent with, for redaction
+
+	compressedSize int64 // bytes received before decompression, 0 if not compressed
+}
+
+// releaseBody returns the body buffer to the pool. Nothing decoded from the
+// body may reference it, which holds since encoding/json copies what it
+// decodes and APIError copies the body.
+func (r *response) releaseBody() {
+	if r.release != nil {
+		r.release()
+		r.release = nil
+	}
+	r.body = nil
+}
+
+// fetch performs a single HTTP request and reads the whole body, reporting
+// its timing when a trace callback is configured.
+func (c *Client) fetch(ctx context.Context, reqURL, requestID, apiKey string) (*response, error) {
+	ctx, traceDone := c.withTrace(ctx, requestID)
+	resp, err := c.roundTrip(ctx, reqURL, requestID, apiKey)
+	traceDone(err)
+	return resp, err
+}
+
+// roundTrip performs a single HTTP request and reads the whole body.
+func (c *Client) roundTrip(ctx context.Context, reqURL, requestID, apiKey string) (*response, error) {
+	// Make the request
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set(requestIDHeader, requestID)
+	c.setAcceptEncoding(req)
+
+	// Network timings use the real clock, like the HTTP client timeout
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Keep the API key out of the error message
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = redactURL(req.URL, apiKey)
+		}
+		err = c.clientTimeoutError(err, ctx, time.Since(start))
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, compressedSize, release, err := c.readBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+	duration := time.Since(start)
+
+	if echoed := resp.Header.Get(requestIDHeader); echoed != "" {
+		requestID = echoed
+	}
+
+	return &response{
+		raw:        resp,
+		requestID:  requestID,
+		statusCode: resp.StatusCode,
+		header:     resp.Header.Clone(),
+		url:        resp.Request.URL,
+		body:       body,
+		bodySize:   int64(len(body)),
+		duration:   duration,
+		release:    release,
+		apiKey:     apiKey,
+
+		compressedSize: compressedSize,
+	}, nil
+}
+
+// decode checks resp for errors and decodes its body into into.
+func (c *Client) decode(resp *response, into interface{}) error {
+	// Check for error responses, including error payloads sent with 200
+	if resp.statusCode != http.StatusOK {
+		apiErr := newAPIError(resp.statusCode, resp.body)
+		apiErr.RequestID = resp.requestID
+		apiErr.Meta = resp.meta()
+		return apiErr
+	}
+	if err := detectErrorPayload(resp.statusCode, resp.body); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			apiErr.RequestID = resp.requestID
+			apiErr.Meta = resp.meta()
+		}
+		return err
+	}
+
+	// Parse the response
+	if err := json.Unmarshal(resp.body, into); err != nil {
+		return &DecodeError{
+			StatusCode:  resp.statusCode,
+			ContentType: resp.header.Get("Content-Type"),
+			URL:         redactURL(resp.url, resp.apiKey),
+			Snippet:     bodySnippet(resp.body, resp.apiKey),
+			Err:         err,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package allnewsapi
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// baselineURL builds a request URL exactly like Search and Headlines did
+// before request building was shared between endpoints.
+func baselineURL(baseURL, path, apiKey string, options *SearchOptions) (string, error) {
+	params := url.Values{}
+	params.Add("apikey", apiKey)
+
+	if options != nil {
+		if options.Query != "" {
+			params.Add("q", options.Query)
+		}
+		for _, d := range []struct {
+			name  string
+			value interface{}
+		}{{"startDate", options.StartDate}, {"endDate", options.EndDate}} {
+			if d.value == nil {
+				continue
+			}
+			switch v := d.value.(type) {
+			case string:
+				params.Add(d.name, v)
+			case time.Time:
+				params.Add(d.name, v.Format(time.RFC3339))
+			default:
+				return "", errors.New(d.name + " must be string or time.Time")
+			}
+		}
+		if options.Content != nil {
+			if *options.Content {
+				params.Add("content", "true")
+			} else {
+				params.Add("content", "false")
+			}
+		}
+		for _, l := range []struct {
+			name   string
+			values []string
+		}{
+			{"lang", options.Lang},
+			{"country", options.Country},
+			{"region", options.Region},
+			{"category", options.Category},
+			{"attributes", options.Attributes},
+			{"publisher", options.Publisher},
+		} {
+			if len(l.values) > 0 {
+				params.Add(l.name, strings.Join(l.values, ","))
+			}
+		}
+		if options.Max > 0 {
+			params.Add("max", fmt.Sprintf("%d", options.Max))
+		}
+		if options.Page > 0 {
+			params.Add("page", fmt.Sprintf("%d", options.Page))
+		}
+		if options.SortBy != "" {
+			params.Add("sortby", options.SortBy)
+		}
+		if options.Format != "" {
+			params.Add("format", options.Format)
+		}
+	}
+
+	return fmt.Sprintf("%s%s?%s", baseURL, path, params.Encode()), nil
+}
+
+// urlMatrix lists options whose encoding must not change. Language tags
+// are already normalized ISO 639-1 codes, since other tags are normalized
+// before being sent.
+var urlMatrix = []struct {
+	name    string
+	options *SearchOptions
+}{
+	{"nil", nil},
+	{"empty", &SearchOptions{}},
+	{"query", &SearchOptions{Query: "bitcoin"}},
+	{"query escaping", &SearchOptions{Query: `"climate change" AND (EU OR café) & 100%`}},
+	{"string dates", &SearchOptions{StartDate: "2024-01-01", EndDate: "2024-01-31T23:59:59Z"}},
+	{"time dates", &SearchOptions{
+		StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 1, 31, 12, 30, 0, 0, time.FixedZone("CET", 3600)),
+	}},
+	{"content true", &SearchOptions{Content: Bool(true)}},
+	{"content false", &SearchOptions{Content: Bool(false)}},
+	{"single values", &SearchOptions{
+		Lang:       []string{"en"},
+		Country:    []string{"us"},
+		Region:     []string{"europe"},
+		Category:   []string{"technology"},
+		Attributes: []string{"title"},
+		Publisher:  []string{"bbc.co.uk"},
+	}},
+	{"lists", &SearchOptions{
+		Lang:       []string{"en", "fr", "de"},
+		Country:    []string{"us", "gb"},
+		Region:     []string{"europe", "asia"},
+		Category:   []string{"technology", "business"},
+		Attributes: []string{"title", "description", "content"},
+		Publisher:  []string{"bbc.co.uk", "reuters.com"},
+	}},
+	{"empty lists", &SearchOptions{Lang: []string{}, Country: []string{}, Publisher: []string{}}},
+	{"max and page", &SearchOptions{Max: 100, Page: 7}},
+	{"max one", &SearchOptions{Max: 1}},
+	{"sort and format", &SearchOptions{SortBy: "relevance", Format: "csv"}},
+	{"everything", &SearchOptions{
+		Query:      "election results",
+		StartDate:  time.Date(2023, 11, 5, 0, 0, 0, 0, time.UTC),
+		EndDate:    "2023-11-10",
+		Content:    Bool(true),
+		Lang:       []string{"en", "es"},
+		Country:    []string{"us"},
+		Region:     []string{"north-america"},
+		Category:   []string{"politics"},
+		Max:        50,
+		Attributes: []string{"title", "description"},
+		Page:       3,
+		SortBy:     "publishedAt",
+		Publisher:  []string{"apnews.com"},
+		Format:     "json",
+	}},
+}
+
+func TestRequestURLsMatchBaseline(t *testing.T) {
+	server, log := recordingServer(t, `{"totalArticles":0,"articles":[]}`)
+	client := newTestClient(t, server.URL)
+
+	for _, ep := range []struct {
+		path string
+		call func(*SearchOptions) (*SearchResponse, error)
+	}{
+		{"/v1/search", func(o *SearchOptions) (*SearchResponse, error) { return client.Search(o) }},
+		{"/v1/headlines", func(o *SearchOptions) (*SearchResponse, error) { return client.Headlines(o) }},
+	} {
+		for _, tt := range urlMatrix {
+			t.Run(ep.path+"/"+tt.name, func(t *testing.T) {
+				want, err := baselineURL("", ep.path, testAPIKey, tt.options)
+				if err != nil {
+					t.Fatalf("baselineURL: %v", err)
+				}
+
+				before := len(log.all())
+				if _, err := ep.call(tt.options); err != nil {
+					t.Fatalf("call: %v", err)
+				}
+				urls := log.urls()
+				if len(urls) != before+1 {
+					t.Fatalf("got %d requests, want 1", len(urls)-before)
+				}
+				if got := urls[before]; got != want {
+					t.Errorf("URL mismatch\n got: %s\nwant: %s", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestBuildParamsDateErrors(t *testing.T) {
+	for _, options := range []*SearchOptions{
+		{StartDate: 20240101},
+		{EndDate: []byte("2024-01-01")},
+	} {
+		_, err := buildParams(options)
+		_, baselineErr := baselineURL("", "/v1/search", testAPIKey, options)
+		if err == nil || baselineErr == nil {
+			t.Fatalf("buildParams(%+v) error = %v, baseline error = %v; want both to fail", options, err, baselineErr)
+		}
+		if err.Error() != baselineErr.Error() {
+			t.Errorf("error = %q, want %q", err, baselineErr)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package allnewsapi
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// requestIDHeader carries the ID of a call. It is the same for every retry
+// and hedged request of the call.
+const requestIDHeader = "X-Request-ID"
+
+// newRequestID returns a random UUID (version 4).
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// RequestError wraps every error returned by a call that reached the
+// network, adding the ID of the call. Quote the ID when contacting
+// AllNewsAPI support.
+type RequestError struct {
+	ID  string // Request ID of the call
+	Err error  // Underlying error
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("%v (request id %s)", e.Err, e.ID)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// RequestID returns the ID of the call that failed.
+func (e *RequestError) RequestID() string {
+	return e.ID
+}
+
+// RequestIDFromError returns the request ID attached to err, or an empty
+// string when there is none.
+func RequestIDFromError(err error) string {
+	var withID interface{ RequestID() string }
+	if errors.As(err, &withID) {
+		return withID.RequestID()
+	}
+	return ""
+}
+
+// withRequestID attaches requestID to err, and to any APIError it wraps
+// that does not already carry the ID echoed by the server.
+func withRequestID(err error, requestID string) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RequestID == "" {
+		apiErr.RequestID = requestID
+	}
+	return &RequestError{ID: requestID, Err: err}
+}
@@ -0,0 +1,130 @@
+package allnewsapi
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// idServer records the X-Request-ID of every request and answers with the
+// status codes of statuses in turn, echoing echo as the request ID when it
+// is not empty.
+func idServer(t *testing.T, echo string, statuses ...int) (url string, ids func() []string) {
+	var mu sync.Mutex
+	var seen []string
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.Header.Get(requestIDHeader))
+		status := statuses[len(statuses)-1]
+		if len(seen) <= len(statuses) {
+			status = statuses[len(seen)-1]
+		}
+		mu.Unlock()
+
+		if echo != "" {
+			w.Header().Set(requestIDHeader, echo)
+		}
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			w.Write([]byte(`{"totalArticles":0,"articles":[]}`))
+		} else {
+			w.Write([]byte(`{"message":"failed"}`))
+		}
+	})
+	return server.URL, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), seen...)
+	}
+}
+
+func TestRequestIDSameOnRetries(t *testing.T) {
+	url, ids := idServer(t, "", http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK)
+	client := newTestClient(t, url, WithRetry(3), WithBackoffPolicy(Constant{}))
+
+	resp, err := client.Search(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent := ids()
+	if len(sent) != 3 {
+		t.Fatalf("server received %d requests, want 3", len(sent))
+	}
+	for i, id := range sent {
+		if id == "" || id != sent[0] {
+			t.Errorf("attempt %d sent request ID %q, want %q", i+1, id, sent[0])
+		}
+	}
+	if resp.RequestID != sent[0] {
+		t.Errorf("RequestID = %q, want the sent ID %q", resp.RequestID, sent[0])
+	}
+}
+
+func TestRequestIDPerCall(t *testing.T) {
+	url, ids := idServer(t, "", http.StatusOK)
+	client := newTestClient(t, url)
+
+	resp, err := client.Search(nil, WithRequestID("call-id"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Search(nil); err != nil {
+		t.Fatal(err)
+	}
+	sent := ids()
+	if sent[0] != "call-id" || resp.RequestID != "call-id" {
+		t.Errorf("sent %q and reported %q, want call-id", sent[0], resp.RequestID)
+	}
+	if sent[1] == "" || sent[1] == "call-id" {
+		t.Errorf("the next call sent request ID %q, want a generated one", sent[1])
+	}
+}
+
+func TestRequestIDInErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		echo    string
+		callID  string
+		wantAPI string // ID in the APIError, empty for the sent one
+	}{
+		{"generated", "", "", ""},
+		{"per call", "", "call-id", "call-id"},
+		{"echoed by the server", "server-id", "call-id", "server-id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, ids := idServer(t, tt.echo, http.StatusServiceUnavailable)
+			client := newTestClient(t, url, WithRetry(2), WithBackoffPolicy(Constant{}))
+
+			var callOpts []CallOption
+			if tt.callID != "" {
+				callOpts = append(callOpts, WithRequestID(tt.callID))
+			}
+			_, err := client.Search(nil, callOpts...)
+			sent := ids()
+			if len(sent) != 2 || sent[0] != sent[1] {
+				t.Fatalf("server received request IDs %q, want the same ID twice", sent)
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("error = %v, want an APIError", err)
+			}
+			want := tt.wantAPI
+			if want == "" {
+				want = sent[0]
+			}
+			if apiErr.RequestID != want {
+				t.Errorf("APIError.RequestID = %q, want %q", apiErr.RequestID, want)
+			}
+			if got := RequestIDFromError(err); got != sent[0] {
+				t.Errorf("RequestIDFromError = %q, want the sent ID %q", got, sent[0])
+			}
+		})
+	}
+
+	if got := RequestIDFromError(errors.New("plain")); got != "" {
+		t.Errorf("RequestIDFromError on a plain error = %q, want empty", got)
+	}
+}
@@ -0,0 +1,317 @@
+package allnewsapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// SearchResponse represents the response from the search endpoint.
+type SearchResponse struct {
+	TotalArticles int64     `json:"totalArticles"`
+	CurrentPage   int       `json:"currentPage"`
+	NextPage      *int      `json:"nextPage"`
+	PrevPage      *int      `json:"prevPage"`
+	TotalPages    int       `json:"totalPages"` // 0 when not provided by the API
+	Articles      []Article `json:"articles"`
+
+	// DecodeReport lists the articles skipped while decoding. It is only set
+	// when the client uses WithLenientDecoding.
+	DecodeReport *DecodeReport `json:"-"`
+
+	// RateLimit is the rate limit state reported with the response, or nil
+	// when the API did not send rate limit headers.
+	RateLimit *RateLimit `json:"-"`
+
+	// RequestID identifies the request for support purposes. It is the ID
+	// echoed by the server when present, otherwise the one sent by the SDK.
+	RequestID string `json:"-"`
+
+	// Meta describes the HTTP response, including its headers.
+	Meta *Meta `json:"-"`
+}
+
+// DecodeReport describes articles skipped by lenient decoding.
+type DecodeReport struct {
+	Skipped []SkippedArticle
+}
+
+// SkippedArticle is an article that could not be decoded.
+type SkippedArticle struct {
+	Index int             // Position in the articles array of the response
+	Err   error           // Decoding error
+	Raw   json.RawMessage // Raw JSON of the article
+}
+
+// decodeOptions controls how response bodies are decoded. The zero value
+// ignores unknown fields and fails on malformed articles.
+type decodeOptions struct {
+	lenient        bool // skip malformed articles
+	strict         bool // fail on unknown fields
+	captureUnknown bool // keep unknown article fields, see Article.Unknown
+	sizeHint       int  // expected number of articles, 0 if unknown
+}
+
+// UnmarshalJSON decodes a SearchResponse. Numeric fields are accepted both as
+// JSON numbers and as string-encoded numbers, which some proxies produce.
+func (r *SearchResponse) UnmarshalJSON(data []byte) error {
+	return r.decode(data, decodeOptions{})
+}
+
+// decode decodes data into r. In lenient mode articles are decoded one by
+// one and the ones that fail are recorded in r.DecodeReport instead of
+// failing the whole response. In strict mode unknown fields are rejected.
+func (r *SearchResponse) decode(data []byte, opts decodeOptions) error {
+	if !opts.lenient && !opts.strict && !opts.captureUnknown {
+		if err := r.decodeDirect(data, opts.sizeHint); err == nil {
+			return nil
+		}
+		// Decode again to report which article failed
+	}
+
+	var aux struct {
+		pageFields
+		Articles json.RawMessage `json:"articles"`
+	}
+	if err := unmarshal(data, &aux, opts.strict); err != nil {
+		return err
+	}
+
+	if err := r.decodeArticles(aux.Articles, opts); err != nil {
+		return err
+	}
+
+	var err error
+	r.TotalArticles, r.CurrentPage, r.NextPage, r.PrevPage, r.TotalPages, err = aux.parse()
+	return err
+}
+
+// decodeDirect decodes data with the default options, decoding articles in
+// place instead of through an intermediate copy of each one. The articles
+// slice is preallocated for sizeHint articles.
+func (r *SearchResponse) decodeDirect(data []byte, sizeHint int) error {
+	var aux struct {
+		pageFields
+		Articles []Article `json:"articles"`
+	}
+	if sizeHint > 0 {
+		aux.Articles = make([]Article, 0, sizeHint)
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	r.Articles = aux.Articles
+	r.DecodeReport = nil
+	var err error
+	r.TotalArticles, r.CurrentPage, r.NextPage, r.PrevPage, r.TotalPages, err = aux.parse()
+	return err
+}
+
+// pageFields holds the raw totals and pagination fields of a response.
+type pageFields struct {
+	TotalArticles json.RawMessage `json:"totalArticles"`
+	CurrentPage   json.RawMessage `json:"currentPage"`
+	NextPage      json.RawMessage `json:"nextPage"`
+	PrevPage      json.RawMessage `json:"prevPage"`
+	TotalPages    json.RawMessage `json:"totalPages"`
+}
+
+// parse decodes the fields, accepting numbers and string-encoded numbers.
+func (f *pageFields) parse() (total int64, current int, next, prev *int, pages int, err error) {
+	if total, _, err = parseNumber("totalArticles", f.TotalArticles); err != nil {
+		return
+	}
+
+	var n int64
+	if n, _, err = parseNumber("currentPage", f.CurrentPage); err != nil {
+		return
+	}
+	current = int(n)
+
+	if next, err = parsePage("nextPage", f.NextPage); err != nil {
+		return
+	}
+	if prev, err = parsePage("prevPage", f.PrevPage); err != nil {
+		return
+	}
+
+	if n, _, err = parseNumber("totalPages", f.TotalPages); err != nil {
+		return
+	}
+	pages = int(n)
+	return
+}
+
+// decodeArticles decodes the articles array into r.Articles.
+func (r *SearchResponse) decodeArticles(raw json.RawMessage, opts decodeOptions) error {
+	r.Articles = nil
+	if opts.lenient {
+		r.DecodeReport = &DecodeReport{}
+	}
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return err
+	}
+	if items != nil {
+		r.Articles = make([]Article, 0, len(items))
+	}
+	for i, item := range items {
+		var article Article
+		if err := article.decode(item, opts); err != nil {
+			if !opts.lenient {
+				return fmt.Errorf("article %d: %w", i, err)
+			}
+			r.DecodeReport.Skipped = append(r.DecodeReport.Skipped, SkippedArticle{
+				Index: i,
+				Err:   err,
+				Raw:   item,
+			})
+			continue
+		}
+		r.Articles = append(r.Articles, article)
+	}
+
+	return nil
+}
+
+// optionsResponse decodes a SearchResponse with the given decode options.
+type optionsResponse struct {
+	*SearchResponse
+	opts decodeOptions
+}
+
+func (o *optionsResponse) UnmarshalJSON(data []byte) error {
+	return o.decode(data, o.opts)
+}
+
+// unmarshal decodes data into v, rejecting unknown fields when strict is
+// set.
+func unmarshal(data []byte, v interface{}, strict bool) error {
+	if !strict {
+		return json.Unmarshal(data, v)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// parsePage decodes an optional page number, returning nil when it is absent
+// or null.
+func parsePage(field string, raw json.RawMessage) (*int, error) {
+	n, ok, err := parseNumber(field, raw)
+	if err != nil || !ok {
+		return nil, err
+	}
+	page := int(n)
+	return &page, nil
+}
+
+// parseNumber decodes an integer that may be encoded as a JSON number or a
+// JSON string. It reports false when the value is absent or null.
+func parseNumber(field string, raw json.RawMessage) (int64, bool, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return 0, false, nil
+	}
+
+	s := string(raw)
+	if raw[0] == '"' {
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return 0, false, fmt.Errorf("invalid %s value %s: %w", field, raw, err)
+		}
+		if s == "" {
+			return 0, false, nil
+		}
+	}
+
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, true, nil
+	}
+
+	// Accept integral values written in float notation, e.g. 1e6 or 12.0
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != math.Trunc(f) || math.Abs(f) >= math.MaxInt64 {
+		return 0, false, fmt.Errorf("invalid %s value %s: not an integer", field, raw)
+	}
+	return int64(f), true, nil
+}
+
+// HasNextPage reports whether the API indicated another page of results.
+// A NextPage that does not advance past CurrentPage is treated as the last
+// page to avoid fetching the same page forever.
+func (r *SearchResponse) HasNextPage() bool {
+	_, ok := r.NextPageNumber()
+	return ok
+}
+
+// NextPageNumber returns the next page number and whether there is one. When
+// the API reports TotalPages, no page beyond it is returned.
+func (r *SearchResponse) NextPageNumber() (int, bool) {
+	if r == nil || r.NextPage == nil || *r.NextPage <= r.CurrentPage {
+		return 0, false
+	}
+	if r.TotalPages > 0 && *r.NextPage > r.TotalPages {
+		return 0, false
+	}
+	return *r.NextPage, true
+}
+
+// TotalPagesEstimate returns TotalPages when the API provides it. Otherwise
+// it derives the number of pages from TotalArticles and the size of this
+// page, which is exact for every page but the last.
+func (r *SearchResponse) TotalPagesEstimate() int {
+	if r == nil {
+		return 0
+	}
+	if r.TotalPages > 0 {
+		return r.TotalPages
+	}
+	if !r.HasNextPage() && r.CurrentPage > 0 {
+		return r.CurrentPage
+	}
+
+	perPage := int64(len(r.Articles))
+	if perPage == 0 {
+		return 0
+	}
+	return int((r.TotalArticles + perPage - 1) / perPage)
+}
+
+// NextPageOptions returns a copy of prev with Page set to the next page, or
+// nil when there is no next page. prev is not modified.
+func (r *SearchResponse) NextPageOptions(prev *SearchOptions) *SearchOptions {
+	page, ok := r.NextPageNumber()
+	if !ok {
+		return nil
+	}
+
+	next := prev.Clone()
+	if next == nil {
+		next = &SearchOptions{}
+	}
+	return next.SetPage(page)
+}
+
+// MergeResponses combines responses into one, skipping nil values. Articles
+// are concatenated in order; use DedupeArticles on the result to drop
+// duplicates. TotalArticles is the sum of the totals, which overcounts
+// articles present in several responses. The pagination fields, RateLimit,
+// RequestID and Meta do not apply to a merged set and are left zero.
+func MergeResponses(responses ...*SearchResponse) *SearchResponse {
+	merged := &SearchResponse{}
+	for _, r := range responses {
+		if r == nil {
+			continue
+		}
+		merged.TotalArticles += r.TotalArticles
+		merged.Articles = append(merged.Articles, r.Articles...)
+	}
+	return merged
+}
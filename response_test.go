@@ -0,0 +1,291 @@
+package allnewsapi
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDecodeNumbers(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		total     int64
+		current   int
+		next      *int
+		pages     int
+		wantError string
+	}{
+		{"numbers", `{"totalArticles":42,"currentPage":1,"nextPage":2,"totalPages":3}`, 42, 1, Int(2), 3, ""},
+		{"strings", `{"totalArticles":"42","currentPage":"1","nextPage":"2","totalPages":"3"}`, 42, 1, Int(2), 3, ""},
+		{"beyond int32", `{"totalArticles":5000000000}`, 5000000000, 0, nil, 0, ""},
+		{"max int64", `{"totalArticles":` + strconv.FormatInt(math.MaxInt64, 10) + `}`, math.MaxInt64, 0, nil, 0, ""},
+		{"large string", `{"totalArticles":"9007199254740993"}`, 9007199254740993, 0, nil, 0, ""},
+		{"float notation", `{"totalArticles":1e6,"currentPage":2.0}`, 1000000, 2, nil, 0, ""},
+		{"null", `{"totalArticles":null,"currentPage":null,"nextPage":null,"totalPages":null}`, 0, 0, nil, 0, ""},
+		{"absent", `{}`, 0, 0, nil, 0, ""},
+		{"empty string", `{"totalArticles":"","nextPage":""}`, 0, 0, nil, 0, ""},
+		{"fraction", `{"totalArticles":1.5}`, 0, 0, nil, 0, "invalid totalArticles value 1.5: not an integer"},
+		{"not a number", `{"currentPage":"one"}`, 0, 0, nil, 0, `invalid currentPage value "one": not an integer`},
+		{"overflow", `{"totalArticles":1e20}`, 0, 0, nil, 0, "invalid totalArticles value 1e20: not an integer"},
+		{"max int64 plus one", `{"totalArticles":9223372036854775808}`, 0, 0, nil, 0, "invalid totalArticles value 9223372036854775808: not an integer"},
+		{"max int64 plus one in float notation", `{"totalArticles":9.223372036854775808e18}`, 0, 0, nil, 0, "invalid totalArticles value 9.223372036854775808e18: not an integer"},
+		{"boolean", `{"totalPages":true}`, 0, 0, nil, 0, "invalid totalPages value true: not an integer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp SearchResponse
+			err := json.Unmarshal([]byte(tt.body), &resp)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("Unmarshal error = %v, want %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if resp.TotalArticles != tt.total || resp.CurrentPage != tt.current || resp.TotalPages != tt.pages {
+				t.Errorf("got total %d, current %d, pages %d; want %d, %d, %d",
+					resp.TotalArticles, resp.CurrentPage, resp.TotalPages, tt.total, tt.current, tt.pages)
+			}
+			if (resp.NextPage == nil) != (tt.next == nil) || (tt.next != nil && *resp.NextPage != *tt.next) {
+				t.Errorf("NextPage = %v, want %v", resp.NextPage, tt.next)
+			}
+		})
+	}
+}
+
+// brokenArticlesBody has five articles, of which those at index 1 and 3
+// cannot be decoded.
+const brokenArticlesBody = `{
+	"totalArticles": 5,
+	"articles": [
+		{"title": "first", "url": "https://example.com/1"},
+		{"title": 42, "url": "https://example.com/2"},
+		{"title": "third", "url": "https://example.com/3"},
+		"not an article",
+		{"title": "fifth", "url": "https://example.com/5"}
+	]
+}`
+
+func TestLenientDecodingReport(t *testing.T) {
+	server, _ := recordingServer(t, brokenArticlesBody)
+	client := newTestClient(t, server.URL, WithLenientDecoding())
+
+	resp, err := client.Search(nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if resp.TotalArticles != 5 {
+		t.Errorf("TotalArticles = %d, want 5", resp.TotalArticles)
+	}
+
+	var titles []string
+	for _, a := range resp.Articles {
+		titles = append(titles, a.Title)
+	}
+	if got := strings.Join(titles, ","); got != "first,third,fifth" {
+		t.Errorf("decoded articles = %s, want first,third,fifth", got)
+	}
+
+	if resp.DecodeReport == nil {
+		t.Fatal("DecodeReport is nil")
+	}
+	skipped := resp.DecodeReport.Skipped
+	if len(skipped) != 2 {
+		t.Fatalf("skipped %d articles, want 2", len(skipped))
+	}
+	for i, want := range []struct {
+		index int
+		raw   string
+	}{
+		{1, `{"title": 42, "url": "https://example.com/2"}`},
+		{3, `"not an article"`},
+	} {
+		s := skipped[i]
+		if s.Index != want.index || string(s.Raw) != want.raw || s.Err == nil {
+			t.Errorf("skipped[%d] = {Index: %d, Raw: %s, Err: %v}, want index %d, raw %s and an error",
+				i, s.Index, s.Raw, s.Err, want.index, want.raw)
+		}
+	}
+}
+
+func TestArticleErrorNamesIndex(t *testing.T) {
+	server, _ := recordingServer(t, brokenArticlesBody)
+	client := newTestClient(t, server.URL)
+
+	_, err := client.Search(nil)
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("Search error = %v, want a DecodeError", err)
+	}
+	if !strings.Contains(err.Error(), "article 1") {
+		t.Errorf("error %q does not name the failing article", err)
+	}
+}
+
+func TestLenientDecodingWithoutFailures(t *testing.T) {
+	server, _ := recordingServer(t, `{"totalArticles":1,"articles":[{"title":"ok"}]}`)
+	client := newTestClient(t, server.URL, WithLenientDecoding())
+
+	resp, err := client.Search(nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(resp.Articles) != 1 || resp.DecodeReport == nil || len(resp.DecodeReport.Skipped) != 0 {
+		t.Errorf("got %d articles and report %+v, want 1 article and an empty report", len(resp.Articles), resp.DecodeReport)
+	}
+}
+
+func TestMergeResponses(t *testing.T) {
+	articles := testArticles(4)
+	next := 2
+	page := func(total int64, articles ...Article) *SearchResponse {
+		return &SearchResponse{TotalArticles: total, CurrentPage: 1, NextPage: &next, RequestID: "id",
+			RateLimit: &RateLimit{Remaining: 1}, Articles: articles}
+	}
+	tests := []struct {
+		name      string
+		responses []*SearchResponse
+		total     int64
+		want      []Article // after DedupeArticles
+		merged    int       // articles before deduping
+	}{
+		{"none", nil, 0, nil, 0},
+		{"nil and empty", []*SearchResponse{nil, {}, page(0)}, 0, nil, 0},
+		{"disjoint", []*SearchResponse{page(5, articles[0], articles[1]), nil, page(7, articles[2])},
+			12, articles[:3], 3},
+		{"overlapping", []*SearchResponse{page(5, articles[0], articles[1]), page(6, articles[1], articles[2], articles[0])},
+			11, articles[:3], 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := MergeResponses(tt.responses...)
+			if merged.TotalArticles != tt.total || len(merged.Articles) != tt.merged {
+				t.Errorf("merged %d articles with total %d, want %d with total %d", len(merged.Articles), merged.TotalArticles, tt.merged, tt.total)
+			}
+			if merged.NextPage != nil || merged.CurrentPage != 0 || merged.RequestID != "" || merged.RateLimit != nil {
+				t.Errorf("merged = %+v, want no pagination, request ID or rate limit", merged)
+			}
+
+			deduped := DedupeArticles(merged.Articles)
+			if len(deduped) != len(tt.want) {
+				t.Fatalf("deduped %s, want %s", titles(deduped), titles(tt.want))
+			}
+			for i := range deduped {
+				if deduped[i].URL != tt.want[i].URL {
+					t.Errorf("deduped %s, want %s", titles(deduped), titles(tt.want))
+				}
+			}
+		})
+	}
+
+	// The merged articles do not alias the inputs
+	first := page(1, articles[0])
+	merged := MergeResponses(first)
+	merged.Articles[0].Title = "changed"
+	if first.Articles[0].Title != articles[0].Title {
+		t.Error("modifying the merged articles modified an input")
+	}
+}
+
+func TestNextPage(t *testing.T) {
+	prev := &SearchOptions{Query: "q", Page: 2}
+	tests := []struct {
+		name     string
+		resp     *SearchResponse
+		prev     *SearchOptions
+		wantNext int // 0 when there is no next page
+	}{
+		{"nil response", nil, prev, 0},
+		{"no next page", &SearchResponse{CurrentPage: 2}, prev, 0},
+		{"next equal to current", &SearchResponse{CurrentPage: 2, NextPage: Int(2)}, prev, 0},
+		{"next behind current", &SearchResponse{CurrentPage: 2, NextPage: Int(1)}, prev, 0},
+		{"next past total pages", &SearchResponse{CurrentPage: 2, NextPage: Int(3), TotalPages: 2}, prev, 0},
+		{"next page", &SearchResponse{CurrentPage: 2, NextPage: Int(3), TotalPages: 3}, prev, 3},
+		{"without total pages", &SearchResponse{CurrentPage: 2, NextPage: Int(3)}, prev, 3},
+		{"nil prev", &SearchResponse{CurrentPage: 1, NextPage: Int(2)}, nil, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, ok := tt.resp.NextPageNumber()
+			if page != tt.wantNext || ok != (tt.wantNext > 0) {
+				t.Errorf("NextPageNumber = %d, %v; want %d", page, ok, tt.wantNext)
+			}
+			if got := tt.resp.HasNextPage(); got != (tt.wantNext > 0) {
+				t.Errorf("HasNextPage = %v", got)
+			}
+
+			next := tt.resp.NextPageOptions(tt.prev)
+			if tt.wantNext == 0 {
+				if next != nil {
+					t.Errorf("NextPageOptions = %+v, want nil", next)
+				}
+				return
+			}
+			if next == nil || next.Page != tt.wantNext || !next.PageSet() {
+				t.Fatalf("NextPageOptions = %+v, want page %d", next, tt.wantNext)
+			}
+			if tt.prev != nil && (next == tt.prev || next.Query != tt.prev.Query) {
+				t.Errorf("NextPageOptions = %+v, want a copy of prev", next)
+			}
+		})
+	}
+	if prev.Page != 2 {
+		t.Errorf("NextPageOptions modified prev: %+v", prev)
+	}
+}
+
+func TestStrictDecoding(t *testing.T) {
+	tests := []struct {
+		name, body, wantErr string
+	}{
+		{"known fields", `{"totalArticles":1,"currentPage":1,"articles":[{"title":"t","source":{"name":"n"}}]}`, ""},
+		{"unknown top-level field", `{"totalArticles":1,"extra":1,"articles":[]}`, `unknown field "extra"`},
+		{"unknown article field", `{"totalArticles":2,"articles":[{"title":"t"},{"title":"a","author":"x"}]}`,
+			`article 1: json: unknown field "author"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := recordingServer(t, tt.body)
+			lax := newTestClient(t, server.URL)
+			strict := newTestClient(t, server.URL, WithStrictDecoding())
+
+			for _, ep := range []struct {
+				name      string
+				lax, call func(*SearchOptions, ...CallOption) (*SearchResponse, error)
+			}{
+				{"search", lax.Search, strict.Search},
+				{"headlines", lax.Headlines, strict.Headlines},
+			} {
+				// Unknown fields are ignored by default
+				if _, err := ep.lax(nil); err != nil {
+					t.Fatalf("%s: %v", ep.name, err)
+				}
+
+				_, err := ep.call(nil)
+				if tt.wantErr == "" {
+					if err != nil {
+						t.Errorf("strict %s: %v", ep.name, err)
+					}
+					continue
+				}
+				var decodeErr *DecodeError
+				if !errors.As(err, &decodeErr) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("strict %s error = %v, want a DecodeError with %q", ep.name, err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestStrictAndLenientRejected(t *testing.T) {
+	_, err := NewClient(testAPIKey, WithLenientDecoding(), WithStrictDecoding())
+	if err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+		t.Errorf("NewClient error = %v, want the options rejected", err)
+	}
+}
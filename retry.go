@@ -0,0 +1,115 @@
+package allnewsapi
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// WithRetry makes the client retry failed requests up to maxAttempts attempts
+// in total, waiting between attempts as decided by the backoff policy
+// (exponential backoff unless set with WithBackoffPolicy). Only errors
+// for which IsRetryable returns true are retried.
+func WithRetry(maxAttempts int) ClientOption {
+	return WithRetryBudget(maxAttempts, 0)
+}
+
+// WithRetryBudget is like WithRetry, but also stops retrying once the next
+// attempt would start more than maxElapsed after the first one. A zero
+// maxElapsed means no time limit. Retrying also stops early when the next
+// attempt would start after the context deadline.
+func WithRetryBudget(maxAttempts int, maxElapsed time.Duration) ClientOption {
+	return func(c *Client) {
+		c.maxAttempts = maxAttempts
+		c.maxElapsed = maxElapsed
+	}
+}
+
+// RetryError is returned when a call failed after being retried. It wraps
+// the error of the last attempt.
+type RetryError struct {
+	Attempts int           // Number of attempts made
+	Elapsed  time.Duration // Time spent on all attempts and backoff
+	Err      error         // Error of the last attempt
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("giving up after %d attempts in %s: %v", e.Attempts, e.Elapsed.Round(time.Millisecond), e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// retryError wraps err in a RetryError when more than one attempt was made.
+func retryError(err error, attempts int, elapsed time.Duration) error {
+	if err == nil || attempts < 2 {
+		return err
+	}
+	return &RetryError{Attempts: attempts, Elapsed: elapsed, Err: err}
+}
+
+// IsRetryable reports whether err is a transient failure that is worth
+// retrying: rate limiting and server errors reported by the API, network
+// timeouts, connection resets and refusals, responses cut short, and
+// temporary DNS failures. Context cancellation, deadlines and TLS
+// certificate errors are never retryable.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	// Certificate problems do not go away by retrying
+	var unknownAuthority x509.UnknownAuthorityError
+	var invalidCert x509.CertificateInvalidError
+	var hostname x509.HostnameError
+	if errors.As(err, &unknownAuthority) || errors.As(err, &invalidCert) || errors.As(err, &hostname) {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// sleep waits for d or until ctx is done, returning the context error in the
+// latter case.
+func sleep(ctx context.Context, clock Clock, d time.Duration) error {
+	timer := clock.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C():
+		return nil
+	}
+}
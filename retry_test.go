@@ -0,0 +1,303 @@
+package allnewsapi_test
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	allnewsapi "github.com/AllNewsAPI/go-sdk"
+	"github.com/AllNewsAPI/go-sdk/allnewsapitest"
+)
+
+// timeoutError is a net.Error reporting a timeout.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errors.New("boom"), false},
+		{"429", &allnewsapi.APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"500", &allnewsapi.APIError{StatusCode: http.StatusInternalServerError}, true},
+		{"502", &allnewsapi.APIError{StatusCode: http.StatusBadGateway}, true},
+		{"503", &allnewsapi.APIError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"504", &allnewsapi.APIError{StatusCode: http.StatusGatewayTimeout}, true},
+		{"400", &allnewsapi.APIError{StatusCode: http.StatusBadRequest}, false},
+		{"401", &allnewsapi.APIError{StatusCode: http.StatusUnauthorized}, false},
+		{"404", &allnewsapi.APIError{StatusCode: http.StatusNotFound}, false},
+		{"wrapped 503", fmt.Errorf("call: %w", &allnewsapi.APIError{StatusCode: 503}), true},
+		{"canceled", context.Canceled, false},
+		{"deadline", fmt.Errorf("call: %w", context.DeadlineExceeded), false},
+		{"unexpected EOF", fmt.Errorf("reading: %w", io.ErrUnexpectedEOF), true},
+		{"EOF", io.EOF, true},
+		{"connection reset", &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, true},
+		{"connection refused", &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, true},
+		{"network timeout", &net.OpError{Op: "read", Err: timeoutError{}}, true},
+		{"temporary DNS failure", &net.DNSError{Err: "server misbehaving", IsTemporary: true}, true},
+		{"DNS timeout", &net.DNSError{Err: "timeout", IsTimeout: true}, true},
+		{"unknown host", &net.DNSError{Err: "no such host", IsNotFound: true}, false},
+		{"unknown authority", x509.UnknownAuthorityError{}, false},
+		{"bad hostname", x509.HostnameError{Host: "example.com", Certificate: &x509.Certificate{}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allnewsapi.IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// newClient returns a client sending requests to baseURL, closed when the
+// test ends.
+func newClient(t testing.TB, baseURL string, opts ...allnewsapi.ClientOption) *allnewsapi.Client {
+	t.Helper()
+	client, err := allnewsapi.NewClient("test-key", append([]allnewsapi.ClientOption{allnewsapi.WithBaseURL(baseURL)}, opts...)...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// attemptCounter records the attempts of the last call through WithMetrics.
+type attemptCounter struct {
+	attempts int32
+}
+
+func (c *attemptCounter) option() allnewsapi.ClientOption {
+	return allnewsapi.WithMetrics(func(m allnewsapi.RequestMetrics) {
+		atomic.StoreInt32(&c.attempts, int32(m.Attempts))
+	})
+}
+
+func (c *attemptCounter) last() int {
+	return int(atomic.LoadInt32(&c.attempts))
+}
+
+const okBody = `{"totalArticles":1,"articles":[{"title":"ok"}]}`
+
+func TestRetryConnectionClosedMidBody(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) > 1 {
+			io.WriteString(w, okBody)
+			return
+		}
+
+		// Promise more bytes than are sent, then drop the connection
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Hijack: %v", err)
+			return
+		}
+		defer conn.Close()
+		fmt.Fprint(buf, "HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nContent-Length: 1000\r\n\r\n")
+		fmt.Fprint(buf, `{"totalArticles":1,"articles":[{"ti`)
+		buf.Flush()
+	}))
+	defer server.Close()
+
+	var counter attemptCounter
+	client := newClient(t, server.URL, allnewsapi.WithRetry(3),
+		allnewsapi.WithBackoffPolicy(allnewsapi.Constant{}), counter.option())
+
+	resp, err := client.Search(nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(resp.Articles) != 1 || counter.last() != 2 {
+		t.Errorf("got %d articles after %d attempts, want 1 article after 2 attempts", len(resp.Articles), counter.last())
+	}
+}
+
+func TestConnectionClosedMidBodyIsRetryable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, buf, _ := w.(http.Hijacker).Hijack()
+		defer conn.Close()
+		fmt.Fprint(buf, "HTTP/1.1 200 OK\r\nContent-Length: 1000\r\n\r\n{")
+		buf.Flush()
+	}))
+	defer server.Close()
+
+	_, err := newClient(t, server.URL).Search(nil)
+	if err == nil || !allnewsapi.IsRetryable(err) {
+		t.Errorf("Search error = %v, want a retryable error", err)
+	}
+}
+
+func TestRetryRefusedConnection(t *testing.T) {
+	// Reserve a port, then close it so connections are refused
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	var counter attemptCounter
+	client := newClient(t, "http://"+addr, allnewsapi.WithRetry(3),
+		allnewsapi.WithBackoffPolicy(allnewsapi.Constant{}), counter.option())
+
+	_, err = client.Search(nil)
+	if !errors.Is(err, syscall.ECONNREFUSED) || !allnewsapi.IsRetryable(err) {
+		t.Fatalf("Search error = %v, want a retryable connection refused error", err)
+	}
+	var retryErr *allnewsapi.RetryError
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 3 || counter.last() != 3 {
+		t.Errorf("error %v after %d attempts, want a RetryError after 3 attempts", err, counter.last())
+	}
+}
+
+func TestNoRetryOnClientError(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+		io.WriteString(w, `{"error":"Invalid API key"}`)
+	}))
+	defer server.Close()
+
+	_, err := newClient(t, server.URL, allnewsapi.WithRetry(3), allnewsapi.WithBackoffPolicy(allnewsapi.Constant{})).Search(nil)
+	var apiErr *allnewsapi.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Search error = %v, want a 401 APIError", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("sent %d requests, want 1", n)
+	}
+}
+
+// unavailableServer answers every request with 503 and counts them.
+func unavailableServer(t *testing.T) (*httptest.Server, *int32) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// searchWaiting runs client.Search and expects it to wait on clock for each
+// of waits in turn. The clock is moved to just before the end of each wait,
+// where the call must still be waiting, and then to its end. It returns the
+// error of the call.
+func searchWaiting(t *testing.T, ctx context.Context, client *allnewsapi.Client, clock *allnewsapitest.FakeClock, waits ...time.Duration) error {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.SearchContext(ctx, nil)
+		done <- err
+	}()
+
+	for i, wait := range waits {
+		clock.BlockUntil(1)
+		clock.Advance(wait - time.Nanosecond)
+		if clock.Waiters() != 1 {
+			t.Fatalf("wait %d ended before %s", i+1, wait)
+		}
+		clock.Advance(time.Nanosecond)
+	}
+	return <-done
+}
+
+func TestRetryBudgetElapsed(t *testing.T) {
+	server, requests := unavailableServer(t)
+	clock := allnewsapitest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	client := newClient(t, server.URL, allnewsapi.WithClock(clock),
+		allnewsapi.WithRetryBudget(10, 5*time.Second),
+		allnewsapi.WithBackoffPolicy(allnewsapi.Constant{Delay: 2 * time.Second}))
+
+	// Attempts start at 0s, 2s and 4s; a fourth would start at 6s
+	err := searchWaiting(t, context.Background(), client, clock, 2*time.Second, 2*time.Second)
+	var retryErr *allnewsapi.RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("Search error = %v, want a RetryError", err)
+	}
+	if retryErr.Attempts != 3 || atomic.LoadInt32(requests) != 3 {
+		t.Errorf("%d attempts, %d requests; want 3 and 3", retryErr.Attempts, atomic.LoadInt32(requests))
+	}
+	if retryErr.Elapsed != 4*time.Second {
+		t.Errorf("Elapsed = %s, want 4s", retryErr.Elapsed)
+	}
+}
+
+func TestRetryBudgetAttempts(t *testing.T) {
+	server, requests := unavailableServer(t)
+	clock := allnewsapitest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	client := newClient(t, server.URL, allnewsapi.WithClock(clock), allnewsapi.WithRetry(4),
+		allnewsapi.WithBackoffPolicy(allnewsapi.Constant{Delay: time.Minute}))
+
+	err := searchWaiting(t, context.Background(), client, clock, time.Minute, time.Minute, time.Minute)
+	var retryErr *allnewsapi.RetryError
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 4 || atomic.LoadInt32(requests) != 4 {
+		t.Errorf("error %v after %d requests, want a RetryError after 4 requests", err, atomic.LoadInt32(requests))
+	}
+	if retryErr != nil && retryErr.Elapsed != 3*time.Minute {
+		t.Errorf("Elapsed = %s, want 3m", retryErr.Elapsed)
+	}
+}
+
+func TestRetryStopsBeforeDeadline(t *testing.T) {
+	server, requests := unavailableServer(t)
+	clock := allnewsapitest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	client := newClient(t, server.URL, allnewsapi.WithClock(clock), allnewsapi.WithRetry(5),
+		allnewsapi.WithBackoffPolicy(allnewsapi.Constant{Delay: time.Hour}))
+
+	// The next attempt would start after the deadline, so the call fails
+	// at once instead of waiting
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	_, err := client.SearchContext(ctx, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Search error = %v, want context.DeadlineExceeded", err)
+	}
+	var apiErr *allnewsapi.APIError
+	if errors.As(err, &apiErr) {
+		t.Errorf("error %v wraps the last API error, want it only quoted", err)
+	}
+	if n := atomic.LoadInt32(requests); n != 1 || clock.Waiters() != 0 {
+		t.Errorf("%d requests and %d waiting timers, want 1 and 0", n, clock.Waiters())
+	}
+}
+
+func TestRetryWaitInterruptedByCancel(t *testing.T) {
+	server, requests := unavailableServer(t)
+	clock := allnewsapitest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	client := newClient(t, server.URL, allnewsapi.WithClock(clock), allnewsapi.WithRetry(5),
+		allnewsapi.WithBackoffPolicy(allnewsapi.Constant{Delay: time.Hour}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.SearchContext(ctx, nil)
+		done <- err
+	}()
+	clock.BlockUntil(1)
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Search error = %v, want context.Canceled", err)
+	}
+	if n := atomic.LoadInt32(requests); n != 1 || clock.Waiters() != 0 {
+		t.Errorf("%d requests and %d waiting timers, want 1 and 0", n, clock.Waiters())
+	}
+}
@@ -0,0 +1,29 @@
+package allnewsapi
+
+import "context"
+
+// Searcher is the set of API calls made by a Client. Accept a Searcher
+// instead of a *Client in your own code to substitute a fake in tests. A
+// fake that embeds Searcher only needs the methods the code under test
+// calls; the others panic if called:
+//
+//	type fakeSearcher struct {
+//		allnewsapi.Searcher
+//		resp *allnewsapi.SearchResponse
+//	}
+//
+//	func (f fakeSearcher) SearchContext(context.Context, *allnewsapi.SearchOptions, ...allnewsapi.CallOption) (*allnewsapi.SearchResponse, error) {
+//		return f.resp, nil
+//	}
+//
+// allnewsapitest.MockClient implements every method.
+type Searcher interface {
+	Search(options *SearchOptions, callOpts ...CallOption) (*SearchResponse, error)
+	SearchContext(ctx context.Context, options *SearchOptions, callOpts ...CallOption) (*SearchResponse, error)
+	Headlines(options *SearchOptions, callOpts ...CallOption) (*SearchResponse, error)
+	HeadlinesContext(ctx context.Context, options *SearchOptions, callOpts ...CallOption) (*SearchResponse, error)
+	Count(ctx context.Context, options *SearchOptions, callOpts ...CallOption) (int64, error)
+	SearchSummaries(ctx context.Context, options *SearchOptions, callOpts ...CallOption) (*SummaryResponse, error)
+}
+
+var _ Searcher = (*Client)(nil)
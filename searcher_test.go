@@ -0,0 +1,98 @@
+package allnewsapi_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	allnewsapi "github.com/AllNewsAPI/go-sdk"
+)
+
+// fakeSearcher serves pages of pageSize articles up to total, recording the
+// endpoint and page of every call, and fails on failPage. It implements only
+// the methods the pagers call.
+type fakeSearcher struct {
+	allnewsapi.Searcher
+	total, pageSize, failPage int
+	calls                     []string
+}
+
+func (f *fakeSearcher) page(endpoint string, options *allnewsapi.SearchOptions) (*allnewsapi.SearchResponse, error) {
+	page := 1
+	if options.PageSet() {
+		page = options.Page
+	}
+	f.calls = append(f.calls, endpoint+" "+strconv.Itoa(page))
+	if page == f.failPage {
+		return nil, errors.New("fake failure")
+	}
+
+	resp := &allnewsapi.SearchResponse{TotalArticles: int64(f.total), CurrentPage: page}
+	for i := (page - 1) * f.pageSize; i < page*f.pageSize && i < f.total; i++ {
+		resp.Articles = append(resp.Articles, allnewsapi.Article{Title: endpoint + " " + strconv.Itoa(i+1)})
+	}
+	if page*f.pageSize < f.total {
+		next := page + 1
+		resp.NextPage = &next
+	}
+	return resp, nil
+}
+
+func (f *fakeSearcher) SearchContext(_ context.Context, options *allnewsapi.SearchOptions, _ ...allnewsapi.CallOption) (*allnewsapi.SearchResponse, error) {
+	return f.page("search", options)
+}
+
+func (f *fakeSearcher) HeadlinesContext(_ context.Context, options *allnewsapi.SearchOptions, _ ...allnewsapi.CallOption) (*allnewsapi.SearchResponse, error) {
+	return f.page("headlines", options)
+}
+
+func TestPagersOverSearcher(t *testing.T) {
+	tests := []struct {
+		name     string
+		newPager func(allnewsapi.Searcher, *allnewsapi.SearchOptions) *allnewsapi.Pager
+		endpoint string
+	}{
+		{"search", allnewsapi.NewSearchPager, "search"},
+		{"headlines", allnewsapi.NewHeadlinesPager, "headlines"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeSearcher{total: 7, pageSize: 3}
+			options := &allnewsapi.SearchOptions{Query: "q"}
+			articles, err := allnewsapi.CollectAll(context.Background(), tt.newPager(fake, options))
+			if err != nil {
+				t.Fatalf("CollectAll: %v", err)
+			}
+
+			if len(articles) != 7 || articles[6].Title != tt.endpoint+" 7" {
+				t.Errorf("got %d articles, want the 7 articles of the %s endpoint", len(articles), tt.endpoint)
+			}
+			want := []string{tt.endpoint + " 1", tt.endpoint + " 2", tt.endpoint + " 3"}
+			if fmt.Sprint(fake.calls) != fmt.Sprint(want) {
+				t.Errorf("calls = %q, want %q", fake.calls, want)
+			}
+			if options.PageSet() {
+				t.Error("the pager modified the options it was given")
+			}
+		})
+	}
+}
+
+func TestPagerOverSearcherStartPageAndError(t *testing.T) {
+	fake := &fakeSearcher{total: 10, pageSize: 2, failPage: 4}
+	options := &allnewsapi.SearchOptions{}
+	options.SetPage(2)
+	articles, err := allnewsapi.CollectAll(context.Background(), allnewsapi.NewSearchPager(fake, options))
+
+	if err == nil || err.Error() != "fake failure" {
+		t.Fatalf("CollectAll error = %v, want the fake failure", err)
+	}
+	if len(articles) != 4 || articles[0].Title != "search 3" {
+		t.Errorf("got %v, want the articles of pages 2 and 3", articles)
+	}
+	if want := "[search 2 search 3 search 4]"; fmt.Sprint(fake.calls) != want {
+		t.Errorf("calls = %v, want %s", fake.calls, want)
+	}
+}
@@ -0,0 +1,97 @@
+package allnewsapi
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// multiLabelSuffixes are common public suffixes with two labels, used to
+// find the registrable domain of hosts such as news.bbc.co.uk. The list is
+// not the full public suffix list; hosts under other multi-label suffixes
+// resolve to the suffix plus one label less than the real registrable
+// domain.
+var multiLabelSuffixes = map[string]bool{
+	"co.uk": true, "org.uk": true, "ac.uk": true, "gov.uk": true, "ltd.uk": true, "plc.uk": true,
+	"com.au": true, "net.au": true, "org.au": true, "gov.au": true,
+	"co.nz": true, "org.nz": true, "govt.nz": true,
+	"co.jp": true, "ne.jp": true, "or.jp": true,
+	"co.kr": true, "or.kr": true,
+	"com.br": true, "com.mx": true, "com.ar": true, "com.co": true,
+	"com.cn": true, "com.hk": true, "com.tw": true, "com.sg": true, "com.my": true,
+	"co.in": true, "net.in": true, "org.in": true,
+	"co.za": true, "co.il": true, "com.tr": true, "com.ua": true,
+	"com.eg": true, "com.ng": true, "co.ke": true, "com.pk": true, "com.ph": true,
+}
+
+// NormalizeSourceURL normalizes a source URL like CanonicalURL, after adding
+// an https scheme to values without one such as "example.com/news". It
+// returns an error when raw has no host.
+func NormalizeSourceURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", errors.New("empty source URL")
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + strings.TrimPrefix(raw, "//")
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid source URL: %w", err)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("invalid source URL %q: no host", raw)
+	}
+	return CanonicalURL(raw), nil
+}
+
+// SourceDomain returns the registrable domain of the article source, for
+// example "bbc.co.uk" for "https://WWW.News.BBC.co.uk/world/". The host is
+// lowercased and stripped of its port before the domain is taken from it;
+// IP addresses are returned as is.
+func (a Article) SourceDomain() (string, error) {
+	raw := a.Source.URL
+	if strings.TrimSpace(raw) == "" {
+		return "", errors.New("article has no source URL")
+	}
+	normalized, err := NormalizeSourceURL(raw)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(normalized)
+	if err != nil {
+		return "", err
+	}
+	return registrableDomain(u.Hostname()), nil
+}
+
+// registrableDomain returns the registrable part of a lowercase host.
+func registrableDomain(host string) string {
+	host = strings.TrimSuffix(host, ".")
+	if net.ParseIP(host) != nil {
+		return host
+	}
+
+	labels := strings.Split(host, ".")
+	n := 2
+	if len(labels) >= 3 && multiLabelSuffixes[strings.Join(labels[len(labels)-2:], ".")] {
+		n = 3
+	}
+	if len(labels) <= n {
+		return host
+	}
+	return strings.Join(labels[len(labels)-n:], ".")
+}
+
+// GroupBySource groups articles by the registrable domain of their source
+// (see Article.SourceDomain). Articles without a usable source URL are left
+// out; their number is returned as skipped.
+func GroupBySource(articles []Article) (groups map[string][]Article, skipped int) {
+	return IndexBy(articles, func(a Article) string {
+		domain, _ := a.SourceDomain()
+		return domain
+	})
+}
@@ -0,0 +1,216 @@
+package allnewsapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ArticleSummary is a compact projection of Article. Decoding into it skips
+// the description, content and other heavy fields without allocating them,
+// which keeps memory low when crawling many pages with Content set.
+type ArticleSummary struct {
+	Title       string
+	URL         string
+	SourceName  string
+	PublishedAt time.Time
+	Sentiment   string
+	Category    string
+
+	// PublishedAtRaw holds the original publishedAt value when it could not
+	// be parsed, in which case PublishedAt is the zero time.
+	PublishedAtRaw string
+}
+
+// skipped accepts any JSON value without storing it. It stands for the
+// Article fields ArticleSummary leaves out, so that strict decoding accepts
+// them without allocating their values.
+type skipped struct{}
+
+func (*skipped) UnmarshalJSON([]byte) error { return nil }
+
+// UnmarshalJSON decodes an ArticleSummary from an article object.
+func (s *ArticleSummary) UnmarshalJSON(data []byte) error {
+	return s.decode(data, false)
+}
+
+// decode decodes data into s, rejecting fields Article does not model when
+// strict is set.
+func (s *ArticleSummary) decode(data []byte, strict bool) error {
+	var aux struct {
+		Title       string          `json:"title"`
+		Description skipped         `json:"description"`
+		Category    string          `json:"category"`
+		Content     skipped         `json:"content"`
+		Country     skipped         `json:"country"`
+		Region      skipped         `json:"region"`
+		Lang        skipped         `json:"lang"`
+		Sentiment   string          `json:"sentiment"`
+		URL         string          `json:"url"`
+		Image       skipped         `json:"image"`
+		PublishedAt json.RawMessage `json:"publishedAt"`
+		Source      struct {
+			Name string  `json:"name"`
+			URL  skipped `json:"url"`
+		} `json:"source"`
+	}
+	if err := unmarshal(data, &aux, strict); err != nil {
+		return err
+	}
+
+	*s = ArticleSummary{
+		Title:      aux.Title,
+		URL:        aux.URL,
+		SourceName: aux.Source.Name,
+		Sentiment:  aux.Sentiment,
+		Category:   aux.Category,
+	}
+	s.PublishedAt, s.PublishedAtRaw = parseTimestamp(aux.PublishedAt)
+	return nil
+}
+
+// Summary returns the summary of a.
+func (a Article) Summary() ArticleSummary {
+	return ArticleSummary{
+		Title:          a.Title,
+		URL:            a.URL,
+		SourceName:     a.Source.Name,
+		PublishedAt:    a.PublishedAt,
+		Sentiment:      a.Sentiment,
+		Category:       a.Category,
+		PublishedAtRaw: a.PublishedAtRaw,
+	}
+}
+
+// SummaryResponse is a search response decoded into article summaries.
+type SummaryResponse struct {
+	TotalArticles int64
+	CurrentPage   int
+	NextPage      *int
+	PrevPage      *int
+	TotalPages    int // 0 when not provided by the API
+	Articles      []ArticleSummary
+
+	RateLimit *RateLimit // nil when the API sent no rate limit headers
+	RequestID string
+	Meta      *Meta
+}
+
+// UnmarshalJSON decodes a SummaryResponse from a search response body.
+func (r *SummaryResponse) UnmarshalJSON(data []byte) error {
+	return r.decode(data, decodeOptions{})
+}
+
+// decode decodes data into r. In strict mode unknown fields are rejected,
+// in the response and in each article; other decode options do not apply.
+func (r *SummaryResponse) decode(data []byte, opts decodeOptions) error {
+	if !opts.strict {
+		var aux struct {
+			pageFields
+			Articles []ArticleSummary `json:"articles"`
+		}
+		if err := json.Unmarshal(data, &aux); err != nil {
+			return err
+		}
+		r.Articles = aux.Articles
+		var err error
+		r.TotalArticles, r.CurrentPage, r.NextPage, r.PrevPage, r.TotalPages, err = aux.parse()
+		return err
+	}
+
+	// Custom unmarshalers do not inherit DisallowUnknownFields, so articles
+	// are decoded through strictSummary
+	var aux struct {
+		pageFields
+		Articles []strictSummary `json:"articles"`
+	}
+	if err := unmarshal(data, &aux, true); err != nil {
+		// Decode again to report which article failed
+		return strictArticleError(data, err)
+	}
+	r.Articles = nil
+	if aux.Articles != nil {
+		r.Articles = make([]ArticleSummary, len(aux.Articles))
+		for i := range aux.Articles {
+			r.Articles[i] = aux.Articles[i].ArticleSummary
+		}
+	}
+	var err error
+	r.TotalArticles, r.CurrentPage, r.NextPage, r.PrevPage, r.TotalPages, err = aux.parse()
+	return err
+}
+
+// strictSummary decodes an ArticleSummary rejecting unknown fields.
+type strictSummary struct {
+	ArticleSummary
+}
+
+func (s *strictSummary) UnmarshalJSON(data []byte) error {
+	return s.decode(data, true)
+}
+
+// strictArticleError returns the error of the first article of data that
+// fails strict decoding, prefixed with its index, or err when the articles
+// decode one by one.
+func strictArticleError(data []byte, err error) error {
+	var aux struct {
+		Articles []json.RawMessage `json:"articles"`
+	}
+	if json.Unmarshal(data, &aux) != nil {
+		return err
+	}
+	for i, item := range aux.Articles {
+		var s ArticleSummary
+		if itemErr := s.decode(item, true); itemErr != nil {
+			return fmt.Errorf("article %d: %w", i, itemErr)
+		}
+	}
+	return err
+}
+
+// summaryOptionsResponse decodes a SummaryResponse with the given decode
+// options.
+type summaryOptionsResponse struct {
+	*SummaryResponse
+	opts decodeOptions
+}
+
+func (o *summaryOptionsResponse) UnmarshalJSON(data []byte) error {
+	return o.decode(data, o.opts)
+}
+
+// NextPageOptions returns a copy of prev with Page set to the next page, or
+// nil when there is no next page, like SearchResponse.NextPageOptions.
+func (r *SummaryResponse) NextPageOptions(prev *SearchOptions) *SearchOptions {
+	if r == nil {
+		return nil
+	}
+	page := &SearchResponse{CurrentPage: r.CurrentPage, NextPage: r.NextPage, TotalPages: r.TotalPages}
+	return page.NextPageOptions(prev)
+}
+
+// SearchSummaries searches for news articles like SearchContext but decodes
+// them into ArticleSummary values, so heavy fields such as Content are never
+// retained. WithStrictDecoding applies to summaries; lenient decoding does
+// not.
+func (c *Client) SearchSummaries(ctx context.Context, options *SearchOptions, callOpts ...CallOption) (*SummaryResponse, error) {
+	cfg := newCallConfig(callOpts)
+
+	params, err := c.params(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries SummaryResponse
+	into := &summaryOptionsResponse{SummaryResponse: &summaries, opts: c.decodeOptions()}
+	resp, err := c.do(ctx, searchEndpoint, params, into, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries.RateLimit = parseRateLimit(resp.header, c.clock.Now())
+	summaries.RequestID = resp.requestID
+	summaries.Meta = resp.meta()
+	return &summaries, nil
+}
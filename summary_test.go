@@ -0,0 +1,85 @@
+package allnewsapi
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSearchSummaries(t *testing.T) {
+	server, _ := recordingServer(t, string(fatResponseBody(3, 500)))
+	resp, err := newTestClient(t, server.URL).SearchSummaries(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("SearchSummaries: %v", err)
+	}
+	if len(resp.Articles) != 3 || resp.TotalArticles != 3 || resp.CurrentPage != 1 || resp.Meta == nil {
+		t.Fatalf("response = %+v, want 3 summaries on page 1", resp)
+	}
+	s := resp.Articles[2]
+	if s.Title != "Article 2" || s.URL != "https://news.example.com/2" || s.SourceName != "Example News" ||
+		s.Sentiment != "positive" || s.Category != "business" || s.PublishedAt.Minute() != 2 {
+		t.Errorf("summary = %+v, want the fields of article 2", s)
+	}
+}
+
+func TestSearchSummariesStrict(t *testing.T) {
+	const article = `"title":"t","description":"d","category":"c","content":"x","country":"us","region":"r",` +
+		`"lang":"en","sentiment":"s","url":"u","image":"i","publishedAt":"2024-03-10T12:00:00Z"`
+	tests := []struct {
+		name, body, wantErr string
+	}{
+		{"all known fields", `{"totalArticles":1,"articles":[{` + article + `,"source":{"name":"n","url":"u"}}]}`, ""},
+		{"unknown top-level field", `{"totalArticles":1,"extra":1,"articles":[]}`, `unknown field "extra"`},
+		{"unknown article field", `{"totalArticles":2,"articles":[{"title":"a"},{` + article + `,"author":"x"}]}`,
+			`article 1: json: unknown field "author"`},
+		{"unknown source field", `{"totalArticles":1,"articles":[{"source":{"name":"n","id":"x"}}]}`, `unknown field "id"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := recordingServer(t, tt.body)
+
+			// Unknown fields are ignored by default
+			if _, err := newTestClient(t, server.URL).SearchSummaries(context.Background(), nil); err != nil {
+				t.Fatalf("SearchSummaries: %v", err)
+			}
+
+			resp, err := newTestClient(t, server.URL, WithStrictDecoding()).SearchSummaries(context.Background(), nil)
+			if tt.wantErr == "" {
+				if err != nil || len(resp.Articles) != 1 || resp.Articles[0].Title != "t" {
+					t.Errorf("strict SearchSummaries = %+v, %v; want the article", resp, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("strict SearchSummaries error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// BenchmarkDecodeSummaries compares the memory used to decode a page of 100
+// articles with large content into summaries and into full articles.
+func BenchmarkDecodeSummaries(b *testing.B) {
+	body := fatResponseBody(100, 20000)
+	b.Run("summaries", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(body)))
+		for i := 0; i < b.N; i++ {
+			var resp SummaryResponse
+			if err := json.Unmarshal(body, &resp); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("articles", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(body)))
+		for i := 0; i < b.N; i++ {
+			var resp SearchResponse
+			if err := json.Unmarshal(body, &resp); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package allnewsapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// TimeoutSource identifies which timeout ended a call.
+type TimeoutSource string
+
+const (
+	TimeoutContext TimeoutSource = "context" // Deadline of the caller's context
+	TimeoutCall    TimeoutSource = "call"    // WithCallTimeout
+	TimeoutClient  TimeoutSource = "client"  // WithTimeout, applied to each attempt
+)
+
+// TimeoutError is returned when a call times out. It wraps the error of the
+// request that was interrupted.
+type TimeoutError struct {
+	Source  TimeoutSource
+	Timeout time.Duration // Configured timeout, 0 for TimeoutContext
+	Err     error
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Source == TimeoutContext {
+		return fmt.Sprintf("context deadline exceeded: %v", e.Err)
+	}
+	return fmt.Sprintf("%s timeout of %s exceeded: %v", e.Source, e.Timeout, e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// WithCallTimeout limits the duration of a single call, including its
+// retries, without affecting other calls. The call ends at the earliest of
+// the caller's context deadline and the call timeout; each attempt is also
+// still limited by the client timeout set with WithTimeout. The resulting
+// error is a *TimeoutError naming the timeout that fired.
+func WithCallTimeout(d time.Duration) CallOption {
+	return func(cfg *callConfig) {
+		cfg.timeout = d
+	}
+}
+
+// withCallTimeout derives the context of a call from the caller's context.
+func withCallTimeout(ctx context.Context, cfg *callConfig) (context.Context, context.CancelFunc) {
+	if cfg.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, cfg.timeout)
+}
+
+// callTimeoutError wraps err in a TimeoutError when the caller's context or
+// the call context expired. parent is the caller's context and ctx the one
+// derived from it by withCallTimeout.
+func callTimeoutError(err error, parent, ctx context.Context, cfg *callConfig) error {
+	var timeoutErr *TimeoutError
+	if err == nil || errors.As(err, &timeoutErr) {
+		return err
+	}
+	switch {
+	case errors.Is(parent.Err(), context.DeadlineExceeded):
+		return &TimeoutError{Source: TimeoutContext, Err: err}
+	case parent.Err() == nil && errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return &TimeoutError{Source: TimeoutCall, Timeout: cfg.timeout, Err: err}
+	}
+	return err
+}
+
+// clientTimeoutError wraps err in a TimeoutError when it is a timeout of an
+// attempt that lasted at least the client timeout while ctx was still live.
+func (c *Client) clientTimeoutError(err error, ctx context.Context, elapsed time.Duration) error {
+	timeout := c.httpClient.Timeout
+	var netErr net.Error
+	if timeout <= 0 || ctx.Err() != nil || elapsed < timeout || !errors.As(err, &netErr) || !netErr.Timeout() {
+		return err
+	}
+	return &TimeoutError{Source: TimeoutClient, Timeout: timeout, Err: err}
+}
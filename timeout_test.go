@@ -0,0 +1,113 @@
+package allnewsapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slowServer starts a server that never answers before the request is
+// cancelled or the test ends.
+func slowServer(t *testing.T) string {
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	return server.URL
+}
+
+func TestTimeoutSources(t *testing.T) {
+	const short, long = 50 * time.Millisecond, 10 * time.Second
+	tests := []struct {
+		name                  string
+		context, call, client time.Duration // 0 when not set
+		want                  TimeoutSource
+	}{
+		{"context", short, 0, 0, TimeoutContext},
+		{"call", 0, short, 0, TimeoutCall},
+		{"client", 0, 0, short, TimeoutClient},
+
+		{"context before call", short, long, 0, TimeoutContext},
+		{"call before context", long, short, 0, TimeoutCall},
+		{"context before client", short, 0, long, TimeoutContext},
+		{"client before context", long, 0, short, TimeoutClient},
+		{"call before client", 0, short, long, TimeoutCall},
+		{"client before call", 0, long, short, TimeoutClient},
+
+		{"context first of all", short, long, long, TimeoutContext},
+		{"call first of all", long, short, long, TimeoutCall},
+		{"client first of all", long, long, short, TimeoutClient},
+	}
+	url := slowServer(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []ClientOption
+			if tt.client > 0 {
+				opts = append(opts, WithTimeout(tt.client))
+			}
+			client := newTestClient(t, url, opts...)
+
+			ctx := context.Background()
+			if tt.context > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.context)
+				defer cancel()
+			}
+			var callOpts []CallOption
+			if tt.call > 0 {
+				callOpts = append(callOpts, WithCallTimeout(tt.call))
+			}
+
+			start := time.Now()
+			_, err := client.SearchContext(ctx, nil, callOpts...)
+			elapsed := time.Since(start)
+
+			var timeoutErr *TimeoutError
+			if !errors.As(err, &timeoutErr) {
+				t.Fatalf("SearchContext error = %v, want a *TimeoutError", err)
+			}
+			if timeoutErr.Source != tt.want {
+				t.Errorf("Source = %s, want %s (error %v)", timeoutErr.Source, tt.want, err)
+			}
+			wantTimeout := map[TimeoutSource]time.Duration{TimeoutContext: 0, TimeoutCall: tt.call, TimeoutClient: tt.client}[tt.want]
+			if timeoutErr.Timeout != wantTimeout {
+				t.Errorf("Timeout = %s, want %s", timeoutErr.Timeout, wantTimeout)
+			}
+			if !strings.Contains(err.Error(), string(tt.want)) {
+				t.Errorf("error %q does not name the %s timeout", err, tt.want)
+			}
+			if elapsed < short || elapsed > long/2 {
+				t.Errorf("call took %s, want about %s", elapsed, short)
+			}
+		})
+	}
+}
+
+func TestCallTimeoutDoesNotAffectOtherCalls(t *testing.T) {
+	server, _ := recordingServer(t, `{"totalArticles":0,"articles":[]}`)
+	client := newTestClient(t, server.URL)
+
+	if _, err := client.Search(nil, WithCallTimeout(time.Nanosecond)); err == nil {
+		t.Fatal("Search with a 1ns call timeout succeeded")
+	}
+	if _, err := client.Search(nil); err != nil {
+		t.Errorf("Search after a timed out call: %v", err)
+	}
+}
+
+func TestCancelledContextIsNotTimeout(t *testing.T) {
+	// The call is cancelled once the request is in flight
+	ctx, cancel := context.WithCancel(context.Background())
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-r.Context().Done()
+	})
+
+	_, err := newTestClient(t, server.URL, WithTimeout(10*time.Second)).SearchContext(ctx, nil, WithCallTimeout(10*time.Second))
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) || !errors.Is(err, context.Canceled) {
+		t.Errorf("SearchContext error = %v, want context.Canceled without a *TimeoutError", err)
+	}
+}
@@ -0,0 +1,115 @@
+package allnewsapi
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// RequestTiming breaks down the time spent on a single HTTP request. Phases
+// that did not happen, such as DNS and connect on a reused connection, are
+// zero.
+type RequestTiming struct {
+	RequestID       string        // Request ID of the call
+	DNS             time.Duration // DNS lookup
+	Connect         time.Duration // TCP connect
+	TLSHandshake    time.Duration // TLS handshake
+	TimeToFirstByte time.Duration // From sending the request to the first response byte
+	Total           time.Duration // From start to the end of reading the body
+	ConnReused      bool          // Whether an idle connection was reused
+	Err             error         // Error of the request, if any
+}
+
+// WithClientTrace registers a callback receiving the timing of every HTTP
+// request, including retries and hedged requests. The callback runs
+// synchronously once the response body was read or the request failed, so it
+// should return quickly.
+func WithClientTrace(fn func(RequestTiming)) ClientOption {
+	return func(c *Client) {
+		c.trace = fn
+	}
+}
+
+// timingRecorder collects RequestTiming using httptrace hooks, which may be
+// called from several goroutines.
+type timingRecorder struct {
+	mu sync.Mutex
+
+	start, dnsStart, connectStart, tlsStart, wroteRequest time.Time
+	timing                                                RequestTiming
+}
+
+// withTrace returns a context recording the timing of the request made with
+// it, and a function reporting the timing to the client callback. Without a
+// callback ctx is returned unchanged.
+func (c *Client) withTrace(ctx context.Context, requestID string) (context.Context, func(error)) {
+	if c.trace == nil {
+		return ctx, func(error) {}
+	}
+
+	rec := &timingRecorder{start: time.Now()}
+	rec.timing.RequestID = requestID
+
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			rec.mu.Lock()
+			rec.timing.ConnReused = info.Reused
+			rec.mu.Unlock()
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			rec.mu.Lock()
+			rec.dnsStart = time.Now()
+			rec.mu.Unlock()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			rec.mu.Lock()
+			rec.timing.DNS = time.Since(rec.dnsStart)
+			rec.mu.Unlock()
+		},
+		ConnectStart: func(string, string) {
+			rec.mu.Lock()
+			if rec.connectStart.IsZero() {
+				rec.connectStart = time.Now()
+			}
+			rec.mu.Unlock()
+		},
+		ConnectDone: func(string, string, error) {
+			rec.mu.Lock()
+			rec.timing.Connect = time.Since(rec.connectStart)
+			rec.mu.Unlock()
+		},
+		TLSHandshakeStart: func() {
+			rec.mu.Lock()
+			rec.tlsStart = time.Now()
+			rec.mu.Unlock()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			rec.mu.Lock()
+			rec.timing.TLSHandshake = time.Since(rec.tlsStart)
+			rec.mu.Unlock()
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			rec.mu.Lock()
+			rec.wroteRequest = time.Now()
+			rec.mu.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			rec.mu.Lock()
+			rec.timing.TimeToFirstByte = time.Since(rec.wroteRequest)
+			rec.mu.Unlock()
+		},
+	}
+
+	done := func(err error) {
+		rec.mu.Lock()
+		timing := rec.timing
+		rec.mu.Unlock()
+
+		timing.Total = time.Since(rec.start)
+		timing.Err = err
+		c.trace(timing)
+	}
+	return httptrace.WithClientTrace(ctx, trace), done
+}
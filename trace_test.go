@@ -0,0 +1,64 @@
+package allnewsapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientTrace(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"totalArticles":0,"articles":[]}`))
+	}))
+	defer server.Close()
+
+	var timings []RequestTiming
+	client := newTestClient(t, server.URL, WithHTTPClient(server.Client()),
+		WithClientTrace(func(rt RequestTiming) { timings = append(timings, rt) }))
+	for i := 0; i < 2; i++ {
+		if _, err := client.Search(nil); err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+	}
+	if len(timings) != 2 {
+		t.Fatalf("got %d timings, want 2", len(timings))
+	}
+
+	first, second := timings[0], timings[1]
+	if first.ConnReused || first.Connect <= 0 || first.TLSHandshake <= 0 {
+		t.Errorf("first request: reused %v, connect %s, TLS %s; want a new connection with both phases timed",
+			first.ConnReused, first.Connect, first.TLSHandshake)
+	}
+	if first.DNS != 0 {
+		t.Errorf("first request: DNS = %s, want 0 for an IP address", first.DNS)
+	}
+	if !second.ConnReused || second.Connect != 0 || second.TLSHandshake != 0 {
+		t.Errorf("second request: reused %v, connect %s, TLS %s; want the reused connection without those phases",
+			second.ConnReused, second.Connect, second.TLSHandshake)
+	}
+	for i, rt := range timings {
+		if rt.TimeToFirstByte <= 0 || rt.Total < rt.TimeToFirstByte || rt.Total < rt.Connect+rt.TLSHandshake {
+			t.Errorf("request %d: TTFB %s, total %s; want a TTFB within the total", i+1, rt.TimeToFirstByte, rt.Total)
+		}
+		if rt.RequestID == "" || rt.Err != nil {
+			t.Errorf("request %d: RequestID %q, Err %v; want an ID and no error", i+1, rt.RequestID, rt.Err)
+		}
+	}
+	if first.RequestID == second.RequestID {
+		t.Error("both calls reported the same request ID")
+	}
+}
+
+func TestClientTraceReportsErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	var timing *RequestTiming
+	client := newTestClient(t, server.URL, WithClientTrace(func(rt RequestTiming) { timing = &rt }))
+	if _, err := client.Search(nil); err == nil {
+		t.Fatal("Search succeeded against a closed server")
+	}
+	if timing == nil || timing.Err == nil || timing.ConnReused {
+		t.Errorf("timing = %+v, want the connection error", timing)
+	}
+}
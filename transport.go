@@ -0,0 +1,184 @@
+package allnewsapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// defaultTimeout is the request timeout used when none is configured.
+const defaultTimeout = 30 * time.Second
+
+// WithHTTPClient sets the http.Client used for requests. The client is
+// copied, so it is not modified by the other options.
+//
+// Transport options are applied on top of it in this order, regardless of
+// the order they are passed in: WithTransport replaces the transport of the
+// client, WithConnectionPool tunes a clone of the resulting transport, and
+// WithTimeout overrides the client timeout.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		c.baseHTTPClient = hc
+	}
+}
+
+// WithTransport sets the http.RoundTripper used for requests. It takes
+// precedence over the transport of a client passed to WithHTTPClient.
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.transport = rt
+	}
+}
+
+// poolConfig holds the settings of WithConnectionPool.
+type poolConfig struct {
+	maxIdleConns        int
+	maxIdleConnsPerHost int
+	maxConnsPerHost     int
+	idleTimeout         time.Duration
+}
+
+// WithConnectionPool configures connection reuse: the maximum number of idle
+// connections overall and per host, the maximum number of connections per
+// host (0 means no limit), and how long idle connections are kept. The
+// settings are applied to a clone of the effective transport (see
+// WithHTTPClient), or of http.DefaultTransport, which is never modified.
+// NewClient fails if the effective transport is not an *http.Transport.
+func WithConnectionPool(maxIdleConns, maxIdleConnsPerHost, maxConnsPerHost int, idleTimeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.pool = &poolConfig{
+			maxIdleConns:        maxIdleConns,
+			maxIdleConnsPerHost: maxIdleConnsPerHost,
+			maxConnsPerHost:     maxConnsPerHost,
+			idleTimeout:         idleTimeout,
+		}
+	}
+}
+
+// WithDialContext sets the function used to open network connections, for
+// example to tunnel through a SOCKS proxy. It replaces the DialContext of a
+// clone of the effective transport and cannot be combined with WithResolver
+// or with a transport that sets DialTLSContext, since either would bypass
+// one of the two settings.
+func WithDialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) ClientOption {
+	return func(c *Client) {
+		c.dialContext = dial
+	}
+}
+
+// WithResolver sets the DNS resolver used to look up the API host. It is
+// used by the client's dialer and cannot be combined with WithDialContext.
+func WithResolver(r *net.Resolver) ClientOption {
+	return func(c *Client) {
+		c.resolver = r
+	}
+}
+
+// WithNetworkFamily restricts connections to IPv4 ("tcp4") or IPv6
+// ("tcp6"); "tcp" keeps the default of using both. It wraps the dialer in
+// use, including one set with WithDialContext, by rewriting the network it
+// is called with. NewClient rejects any other value.
+func WithNetworkFamily(family string) ClientOption {
+	return func(c *Client) {
+		c.networkFamily = family
+	}
+}
+
+// buildHTTPClient assembles c.httpClient from the client options.
+func (c *Client) buildHTTPClient() error {
+	hc := &http.Client{Timeout: defaultTimeout}
+	if c.baseHTTPClient != nil {
+		clone := *c.baseHTTPClient
+		hc = &clone
+	}
+	if c.transport != nil {
+		hc.Transport = c.transport
+	}
+	if c.sharedTransport != nil {
+		if c.transport != nil || c.pool != nil || c.dialContext != nil || c.resolver != nil || c.networkFamily != "" {
+			return errors.New("WithSharedTransport cannot be combined with WithTransport or transport configuration options")
+		}
+		hc.Transport = c.sharedTransport
+	}
+	if c.timeout != nil {
+		hc.Timeout = *c.timeout
+	}
+
+	if c.pool != nil || c.dialContext != nil || c.resolver != nil || c.networkFamily != "" {
+		transport, err := c.configureTransport(hc.Transport)
+		if err != nil {
+			return err
+		}
+		hc.Transport = transport
+		c.ownsTransport = true
+	}
+
+	if check := c.checkRedirect(); check != nil {
+		hc.CheckRedirect = check
+	}
+
+	c.httpClient = hc
+	return nil
+}
+
+// configureTransport returns a clone of rt with the transport options
+// applied. rt must be an *http.Transport; nil means http.DefaultTransport.
+func (c *Client) configureTransport(rt http.RoundTripper) (*http.Transport, error) {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	base, ok := rt.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("transport options require an *http.Transport, got %T", rt)
+	}
+
+	if c.dialContext != nil && c.resolver != nil {
+		return nil, errors.New("WithDialContext and WithResolver cannot be combined; resolve names in the dialer instead")
+	}
+	if (c.dialContext != nil || c.resolver != nil) && base.DialTLSContext != nil {
+		return nil, errors.New("the transport sets DialTLSContext, which bypasses WithDialContext and WithResolver for HTTPS")
+	}
+
+	transport := base.Clone()
+	if c.dialContext != nil {
+		transport.DialContext = c.dialContext
+	}
+	if c.resolver != nil {
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Resolver:  c.resolver,
+		}
+		transport.DialContext = dialer.DialContext
+	}
+	switch c.networkFamily {
+	case "", "tcp":
+	case "tcp4", "tcp6":
+		transport.DialContext = withNetwork(transport.DialContext, c.networkFamily)
+	default:
+		return nil, fmt.Errorf("unsupported network family %q, must be tcp, tcp4 or tcp6", c.networkFamily)
+	}
+	if c.pool != nil {
+		transport.MaxIdleConns = c.pool.maxIdleConns
+		transport.MaxIdleConnsPerHost = c.pool.maxIdleConnsPerHost
+		transport.MaxConnsPerHost = c.pool.maxConnsPerHost
+		transport.IdleConnTimeout = c.pool.idleTimeout
+	}
+	return transport, nil
+}
+
+// withNetwork wraps dial so that TCP connections use the given network.
+func withNetwork(dial func(ctx context.Context, network, addr string) (net.Conn, error), family string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if dial == nil {
+		dial = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if network == "tcp" {
+			network = family
+		}
+		return dial(ctx, network, addr)
+	}
+}
@@ -0,0 +1,179 @@
+package allnewsapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// recordingDialer records the network and address of every dial before
+// connecting over plain TCP.
+type recordingDialer struct {
+	mu    sync.Mutex
+	dials []string
+}
+
+func (d *recordingDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	d.mu.Lock()
+	d.dials = append(d.dials, network+" "+addr)
+	d.mu.Unlock()
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, "tcp", addr)
+}
+
+func (d *recordingDialer) all() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.dials...)
+}
+
+func TestDialContextIsUsed(t *testing.T) {
+	server, _ := recordingServer(t, `{"totalArticles":0,"articles":[]}`)
+	dialer := &recordingDialer{}
+	base := &http.Transport{}
+	client := newTestClient(t, server.URL, WithHTTPClient(&http.Client{Transport: base}),
+		WithDialContext(dialer.DialContext), WithConnectionPool(4, 4, 0, time.Minute))
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Search(nil); err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+	}
+
+	// One connection, reused for the following calls
+	addr := strings.TrimPrefix(server.URL, "http://")
+	if got := dialer.all(); len(got) != 1 || got[0] != "tcp "+addr {
+		t.Errorf("dials = %q, want one dial of %s", got, addr)
+	}
+	if base.DialContext != nil || base.MaxIdleConnsPerHost != 0 {
+		t.Error("the transport passed to WithHTTPClient was modified")
+	}
+	if transport := client.httpClient.Transport.(*http.Transport); transport.MaxIdleConnsPerHost != 4 {
+		t.Errorf("MaxIdleConnsPerHost = %d, want 4", transport.MaxIdleConnsPerHost)
+	}
+}
+
+func TestConfigureTransportErrors(t *testing.T) {
+	dial := (&net.Dialer{}).DialContext
+	tests := []struct {
+		name string
+		opts []ClientOption
+		want string
+	}{
+		{"not an http.Transport", []ClientOption{WithTransport(roundTripFunc(nil)), WithDialContext(dial)},
+			"require an *http.Transport"},
+		{"dialer and resolver", []ClientOption{WithDialContext(dial), WithResolver(&net.Resolver{})},
+			"cannot be combined"},
+		{"DialTLSContext", []ClientOption{WithDialContext(dial),
+			WithTransport(&http.Transport{DialTLSContext: dial})},
+			"bypasses WithDialContext"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClient(testAPIKey, tt.opts...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("NewClient error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNetworkFamily(t *testing.T) {
+	server, _ := recordingServer(t, `{"totalArticles":0,"articles":[]}`)
+	addr := strings.TrimPrefix(server.URL, "http://")
+
+	for _, family := range []string{"", "tcp", "tcp4", "tcp6"} {
+		name := family
+		if name == "" {
+			name = "unset"
+		}
+		t.Run(name, func(t *testing.T) {
+			dialer := &recordingDialer{}
+			client := newTestClient(t, server.URL, WithDialContext(dialer.DialContext), WithNetworkFamily(family))
+			if _, err := client.Search(nil); err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			want := family
+			if want == "" {
+				want = "tcp"
+			}
+			if got := dialer.all(); len(got) != 1 || got[0] != want+" "+addr {
+				t.Errorf("dials = %q, want one dial of %s %s", got, want, addr)
+			}
+		})
+	}
+
+	// Without a dial hook the default dialer is wrapped; the IPv4 test
+	// server cannot be reached over IPv6
+	if _, err := newTestClient(t, server.URL, WithNetworkFamily("tcp4")).Search(nil); err != nil {
+		t.Errorf("Search over tcp4: %v", err)
+	}
+	if _, err := newTestClient(t, server.URL, WithNetworkFamily("tcp6"), WithRetry(1)).Search(nil); err == nil {
+		t.Error("Search over tcp6 reached an IPv4 address")
+	}
+
+	if _, err := NewClient(testAPIKey, WithNetworkFamily("udp")); err == nil || !strings.Contains(err.Error(), "unsupported network family") {
+		t.Errorf("NewClient error = %v, want an unsupported network family error", err)
+	}
+}
+
+// roundTripFunc is an http.RoundTripper that is not an *http.Transport.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	if f == nil {
+		return nil, errors.New("no round trip")
+	}
+	return f(r)
+}
+
+// BenchmarkConnectionPool runs concurrent searches against a TLS server and
+// reports the TLS handshakes per call. The default transport keeps two idle
+// connections per host, so most concurrent calls have to open and secure a
+// new connection; a sized pool keeps them all.
+func BenchmarkConnectionPool(b *testing.B) {
+	for _, bm := range []struct {
+		name string
+		opts []ClientOption
+	}{
+		{"default", nil},
+		{"pooled", []ClientOption{WithConnectionPool(64, 64, 0, time.Minute)}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			var handshakes int64
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"totalArticles":1,"articles":[{"title":"ok"}]}`))
+			}))
+			server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					atomic.AddInt64(&handshakes, 1)
+				}
+			}
+			server.StartTLS()
+			defer server.Close()
+
+			opts := append([]ClientOption{WithHTTPClient(server.Client())}, bm.opts...)
+			client := newTestClient(b, server.URL, opts...)
+
+			b.SetParallelism(16)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := client.Search(nil); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+			b.StopTimer()
+			b.ReportMetric(float64(atomic.LoadInt64(&handshakes))/float64(b.N), "handshakes/op")
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package allnewsapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// day is the length of a window advanced by calendar days.
+const day = 24 * time.Hour
+
+// Window is one date window queried by SearchByWindows.
+type Window struct {
+	Start time.Time
+	End   time.Time
+
+	// TotalArticles is the number of matching articles reported by the API
+	// and Fetched the number actually retrieved. Fetched below TotalArticles
+	// means the window hit the result cap and should be split further.
+	TotalArticles int64
+	Fetched       int
+
+	Err error // Error that ended the window early, if any
+}
+
+// Capped reports whether fewer articles were fetched than the API reported.
+func (w Window) Capped() bool {
+	return int64(w.Fetched) < w.TotalArticles
+}
+
+// WindowedResult is the result of SearchByWindows.
+type WindowedResult struct {
+	Articles []Article // Articles of all windows, deduplicated
+	Windows  []Window  // Windows in chronological order
+}
+
+// SearchByWindows splits the period from options.StartDate to
+// options.EndDate into consecutive windows of the given length, fetches
+// every page of each window with at most concurrency windows in flight, and
+// merges the articles, dropping duplicates returned by adjacent windows. Both
+// dates must be set. Windows that are a whole number of days advance by
+// calendar days in the location of StartDate, so a one-day window spans 23
+// or 25 hours across daylight saving time changes; other windows have a
+// fixed length. The last window ends at EndDate.
+//
+// When some windows fail, the articles of the others are returned along
+// with a *MultiError keyed by window index.
+func (c *Client) SearchByWindows(ctx context.Context, options *SearchOptions, window time.Duration, concurrency int) (*WindowedResult, error) {
+	if options == nil || options.StartDate == nil || options.EndDate == nil {
+		return nil, errors.New("SearchByWindows requires StartDate and EndDate")
+	}
+	start, err := parseDateOption(options.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid StartDate: %w", err)
+	}
+	end, err := parseDateOption(options.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid EndDate: %w", err)
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("EndDate %s is not after StartDate %s", end.Format(time.RFC3339), start.Format(time.RFC3339))
+	}
+
+	bounds, err := splitWindows(start, end, window)
+	if err != nil {
+		return nil, err
+	}
+
+	windows := make([]Window, len(bounds)-1)
+	for i := range windows {
+		windows[i].Start, windows[i].End = bounds[i], bounds[i+1]
+	}
+	articles := make([][]Article, len(windows))
+	err = runConcurrent(ctx, len(windows), concurrency, false, func(ctx context.Context, i int) error {
+		w := &windows[i]
+		opts := options.Clone()
+		opts.StartDate, opts.EndDate = w.Start, w.End
+		pager := c.SearchPager(opts)
+		for pager.HasNext() {
+			page, err := pager.Next(ctx)
+			if err != nil {
+				w.Err = err
+				return err
+			}
+			if w.TotalArticles == 0 {
+				w.TotalArticles = page.TotalArticles
+			}
+			articles[i] = append(articles[i], page.Articles...)
+			w.Fetched += len(page.Articles)
+		}
+		return nil
+	})
+
+	result := &WindowedResult{Windows: windows}
+	for i := range windows {
+		result.Articles = append(result.Articles, articles[i]...)
+	}
+	result.Articles = DedupeArticles(result.Articles)
+	return result, err
+}
+
+// splitWindows returns the boundaries of consecutive windows covering
+// [start, end).
+func splitWindows(start, end time.Time, window time.Duration) ([]time.Time, error) {
+	if window <= 0 {
+		return nil, fmt.Errorf("window must be positive, got %s", window)
+	}
+
+	bounds := []time.Time{start}
+	for t := start; t.Before(end); {
+		if window%day == 0 {
+			t = t.AddDate(0, 0, int(window/day))
+		} else {
+			t = t.Add(window)
+		}
+		if t.After(end) {
+			t = end
+		}
+		bounds = append(bounds, t)
+	}
+	return bounds, nil
+}
+
+// parseDateOption converts a StartDate or EndDate value to a time. Strings
+// are parsed with the layouts accepted for publishedAt, in UTC when they
+// have no zone.
+func parseDateOption(v interface{}) (time.Time, error) {
+	switch d := v.(type) {
+	case time.Time:
+		return d, nil
+	case string:
+		s := strings.TrimSpace(d)
+		for _, layout := range timestampLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("unrecognized date %q", d)
+	default:
+		return time.Time{}, fmt.Errorf("unsupported date type %T", v)
+	}
+}
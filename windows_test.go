@@ -0,0 +1,167 @@
+package allnewsapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSplitWindows(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	at := func(loc *time.Location, month time.Month, day, hour int) time.Time {
+		return time.Date(2024, month, day, hour, 0, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		window     time.Duration
+		want       []time.Time
+	}{
+		{"exact days", at(time.UTC, 3, 1, 0), at(time.UTC, 3, 4, 0), day,
+			[]time.Time{at(time.UTC, 3, 1, 0), at(time.UTC, 3, 2, 0), at(time.UTC, 3, 3, 0), at(time.UTC, 3, 4, 0)}},
+		{"last window cut", at(time.UTC, 3, 1, 0), at(time.UTC, 3, 2, 6), 12 * time.Hour,
+			[]time.Time{at(time.UTC, 3, 1, 0), at(time.UTC, 3, 1, 12), at(time.UTC, 3, 2, 0), at(time.UTC, 3, 2, 6)}},
+		{"shorter than a window", at(time.UTC, 3, 1, 0), at(time.UTC, 3, 1, 5), day,
+			[]time.Time{at(time.UTC, 3, 1, 0), at(time.UTC, 3, 1, 5)}},
+
+		// Day windows keep to midnight across DST changes; hour windows
+		// keep their length
+		{"spring forward days", at(newYork, 3, 9, 0), at(newYork, 3, 12, 0), day,
+			[]time.Time{at(newYork, 3, 9, 0), at(newYork, 3, 10, 0), at(newYork, 3, 11, 0), at(newYork, 3, 12, 0)}},
+		{"fall back days", at(newYork, 11, 2, 0), at(newYork, 11, 4, 0), day,
+			[]time.Time{at(newYork, 11, 2, 0), at(newYork, 11, 3, 0), at(newYork, 11, 4, 0)}},
+		{"spring forward hours", at(newYork, 3, 10, 0), at(newYork, 3, 10, 4), time.Hour,
+			[]time.Time{at(newYork, 3, 10, 0), at(newYork, 3, 10, 1), at(newYork, 3, 10, 3), at(newYork, 3, 10, 4)}},
+
+		// Month and year boundaries, including the leap day
+		{"month boundary", at(time.UTC, 1, 30, 0), at(time.UTC, 2, 2, 0), day,
+			[]time.Time{at(time.UTC, 1, 30, 0), at(time.UTC, 1, 31, 0), at(time.UTC, 2, 1, 0), at(time.UTC, 2, 2, 0)}},
+		{"leap day weeks", at(time.UTC, 2, 20, 0), at(time.UTC, 3, 10, 0), 7 * day,
+			[]time.Time{at(time.UTC, 2, 20, 0), at(time.UTC, 2, 27, 0), at(time.UTC, 3, 5, 0), at(time.UTC, 3, 10, 0)}},
+		{"year boundary", time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), at(time.UTC, 1, 2, 0), day,
+			[]time.Time{time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), at(time.UTC, 1, 1, 0), at(time.UTC, 1, 2, 0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitWindows(tt.start, tt.end, tt.window)
+			if err != nil {
+				t.Fatalf("splitWindows: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("bounds = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("bound %d = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	// The spring forward day is 23 hours long and the fall back day 25
+	bounds, _ := splitWindows(at(newYork, 3, 10, 0), at(newYork, 3, 11, 0), day)
+	if d := bounds[1].Sub(bounds[0]); d != 23*time.Hour {
+		t.Errorf("spring forward day lasts %s, want 23h", d)
+	}
+	bounds, _ = splitWindows(at(newYork, 11, 3, 0), at(newYork, 11, 4, 0), day)
+	if d := bounds[1].Sub(bounds[0]); d != 25*time.Hour {
+		t.Errorf("fall back day lasts %s, want 25h", d)
+	}
+
+	if _, err := splitWindows(at(time.UTC, 3, 1, 0), at(time.UTC, 3, 2, 0), 0); err == nil {
+		t.Error("splitWindows accepted a zero window")
+	}
+}
+
+// windowServer answers searches with the articles of fixtures published
+// between startDate and endDate, both included, as the API does.
+func windowServer(t *testing.T, fixtures []Article) (string, *requestLog) {
+	log := &requestLog{}
+	server := newTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		log.add(r)
+		start, err1 := time.Parse(time.RFC3339, r.URL.Query().Get("startDate"))
+		end, err2 := time.Parse(time.RFC3339, r.URL.Query().Get("endDate"))
+		if err1 != nil || err2 != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		resp := SearchResponse{Articles: []Article{}}
+		for _, a := range fixtures {
+			if !a.PublishedAt.Before(start) && !a.PublishedAt.After(end) {
+				resp.Articles = append(resp.Articles, a)
+			}
+		}
+		resp.TotalArticles = int64(len(resp.Articles))
+		json.NewEncoder(w).Encode(&resp)
+	})
+	return server.URL, log
+}
+
+func TestSearchByWindowsDedupesBoundaries(t *testing.T) {
+	start := time.Date(2024, 1, 30, 0, 0, 0, 0, time.UTC)
+	article := func(title string, at time.Time) Article {
+		return Article{Title: title, URL: "https://example.com/" + title, PublishedAt: at}
+	}
+	fixtures := []Article{
+		article("first", start.Add(time.Hour)),
+		article("on-boundary", start.Add(day)), // returned by the first two windows
+		article("second", start.Add(day+time.Hour)),
+		article("month-end", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)),
+		article("last", time.Date(2024, 2, 1, 23, 0, 0, 0, time.UTC)),
+	}
+	url, log := windowServer(t, fixtures)
+	client := newTestClient(t, url)
+
+	options := &SearchOptions{StartDate: start, EndDate: time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC)}
+	result, err := client.SearchByWindows(context.Background(), options, day, 2)
+	if err != nil {
+		t.Fatalf("SearchByWindows: %v", err)
+	}
+
+	got := titles(result.Articles)
+	want := []string{"first", "on-boundary", "second", "month-end", "last"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("articles = %q, want %q", got, want)
+	}
+
+	fetched := []int{2, 3, 2} // boundary articles count in both windows
+	if len(result.Windows) != len(fetched) || len(log.all()) != len(fetched) {
+		t.Fatalf("got %d windows and %d requests, want %d", len(result.Windows), len(log.all()), len(fetched))
+	}
+	for i, w := range result.Windows {
+		if w.Fetched != fetched[i] || w.Capped() || w.Err != nil {
+			t.Errorf("window %d = %+v, want %d articles fetched", i, w, fetched[i])
+		}
+		if i > 0 && !w.Start.Equal(result.Windows[i-1].End) {
+			t.Errorf("window %d starts at %s, want the end of window %d", i, w.Start, i-1)
+		}
+	}
+
+	// The caller's options are not changed
+	if options.StartDate != start {
+		t.Errorf("StartDate = %v, want it unchanged", options.StartDate)
+	}
+}
+
+func TestSearchByWindowsInvalidDates(t *testing.T) {
+	client := newTestClient(t, "http://127.0.0.1:0")
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for _, options := range []*SearchOptions{
+		nil,
+		{StartDate: start},
+		{StartDate: start, EndDate: start},
+		{StartDate: "yesterday", EndDate: start},
+		{StartDate: start, EndDate: 42},
+	} {
+		if _, err := client.SearchByWindows(context.Background(), options, day, 1); err == nil {
+			t.Errorf("SearchByWindows(%+v) succeeded, want an error", options)
+		}
+	}
+}